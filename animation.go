@@ -7,16 +7,107 @@ import (
 	"github.com/charmbracelet/harmonica"
 )
 
-// AnimationEngine handles smooth animations using Harmonica springs
+// AnimationEngine handles smooth animations using Harmonica springs.
+// Every entity's spring is built from the engine's settings, so changing them
+// changes how all existing entities move from the next update on.
 type AnimationEngine struct {
-	// Animation settings
+	// Animation settings; a tension of zero or less turns smoothing off
 	SpringTension float64 // Spring stiffness (higher = faster convergence)
 	SpringDamping float64 // Spring damping (higher = less oscillation)
 
-	// Frame rate targeting
+	// Frame timing. Each update steps an entity's spring by the time since its last
+	// update, no more than MaxFrameDelta; TargetFPS is the rate the UI ticks at.
+	// LastFrameTime and FrameDelta record the most recent update and its step.
 	TargetFPS     int
+	MaxFrameDelta time.Duration
 	LastFrameTime time.Time
 	FrameDelta    time.Duration
+
+	// Clock returns the current time; tests replace it to drive animation deterministically
+	Clock func() time.Time
+
+	// Display positions kept per entity for motion trails; 0 records none
+	TrailLength int
+
+	// Spring built from the settings above, rebuilt when they or the step change
+	spring         harmonica.Spring
+	springSettings [3]float64 // Tension, damping and step the spring was built with
+
+	frameTime time.Time // Clock reading shared by UpdateEntities; zero outside it
+}
+
+// SpringPreset is a named set of spring settings
+type SpringPreset struct {
+	Name    string
+	Tension float64
+	Damping float64
+}
+
+// springPresets are the spring feels the user can switch between
+var springPresets = []SpringPreset{
+	{Name: "snappy", Tension: 40, Damping: 1},   // Quick and settled, no overshoot
+	{Name: "smooth", Tension: 300, Damping: 30}, // Heavily damped glide; the default
+	{Name: "wobbly", Tension: 12, Damping: 0.2}, // Overshoots and rings before settling
+	{Name: "none", Tension: 0, Damping: 0},      // Entities are drawn exactly where physics has them
+}
+
+// defaultSpringPreset is the index of the preset NewAnimationEngine starts with
+const defaultSpringPreset = 1
+
+// ApplyPreset switches the engine to a preset's spring settings
+func (ae *AnimationEngine) ApplyPreset(preset SpringPreset) {
+	ae.SpringTension = preset.Tension
+	ae.SpringDamping = preset.Damping
+}
+
+// currentSpring returns the spring for the engine's settings and a time step,
+// rebuilding it if either changed. It reports false when smoothing is off.
+func (ae *AnimationEngine) currentSpring(step time.Duration) (harmonica.Spring, bool) {
+	if ae.SpringTension <= 0 {
+		return harmonica.Spring{}, false
+	}
+	damping := math.Max(0, ae.SpringDamping) // Negative damping would make springs blow up
+	settings := [3]float64{ae.SpringTension, damping, step.Seconds()}
+	if settings != ae.springSettings {
+		ae.spring = harmonica.NewSpring(step.Seconds(), ae.SpringTension, damping)
+		ae.springSettings = settings
+	}
+	return ae.spring, true
+}
+
+// frameStep returns how far to advance an animation last updated at lastUpdate:
+// the time since then, capped at MaxFrameDelta. An extra update in the same
+// instant advances nothing.
+func (ae *AnimationEngine) frameStep(now, lastUpdate time.Time) time.Duration {
+	step := now.Sub(lastUpdate)
+	if step < 0 {
+		step = 0
+	}
+	if ae.MaxFrameDelta > 0 && step > ae.MaxFrameDelta {
+		step = ae.MaxFrameDelta
+	}
+	return step
+}
+
+// UpdateEntities advances every entity's animation for one tick. The clock is read
+// once, so entities updated together step by the same time and share one spring.
+func (ae *AnimationEngine) UpdateEntities(entities []Entity) {
+	ae.frameTime = ae.now()
+	defer func() { ae.frameTime = time.Time{} }()
+	for _, entity := range entities {
+		entity.UpdateAnimation(ae)
+	}
+}
+
+// now reads the engine's clock, or the tick's reading during UpdateEntities
+func (ae *AnimationEngine) now() time.Time {
+	if !ae.frameTime.IsZero() {
+		return ae.frameTime
+	}
+	if ae.Clock == nil {
+		return time.Now()
+	}
+	return ae.Clock()
 }
 
 // EntityAnimationState holds animation state for each entity
@@ -30,38 +121,49 @@ type EntityAnimationState struct {
 	// Velocity for spring animation
 	VelocityX, VelocityY float64
 
-	// Spring animations for smooth movement
-	SpringX harmonica.Spring
-	SpringY harmonica.Spring
-
 	// Animation tracking
 	IsAnimating bool
 	LastUpdate  time.Time
+
+	// Recent display positions for motion trails, oldest first, one per cell visited
+	Trail   [][2]float64
+	snapped bool // Moved by SetInitialPosition since the trail was last recorded
+
+	// Impact effects still showing; see ImpactEffects
+	Flash      time.Duration // Drawn brightened until this runs out
+	Squash     time.Duration // Drawn squashed against SquashEdge until this runs out
+	SquashEdge WallEdge
 }
 
 // NewAnimationEngine creates a new animation engine
 func NewAnimationEngine() *AnimationEngine {
 	return &AnimationEngine{
-		SpringTension: 300.0, // Responsive but not too bouncy
-		SpringDamping: 30.0,  // Well-damped
-		TargetFPS:     60,    // 60 FPS for smooth animation
+		SpringTension: springPresets[defaultSpringPreset].Tension,
+		SpringDamping: springPresets[defaultSpringPreset].Damping,
+		TargetFPS:     60,                     // 60 FPS for smooth animation
+		MaxFrameDelta: time.Millisecond * 100, // Long stalls catch up at most 100ms at once
 		LastFrameTime: time.Now(),
 		FrameDelta:    time.Millisecond * 16, // ~60 FPS (16ms per frame)
+		Clock:         time.Now,
 	}
 }
 
 // NewEntityAnimationState creates animation state for an entity
 func (ae *AnimationEngine) NewEntityAnimationState(x, y float64) *EntityAnimationState {
+	state := newEntityAnimationState(x, y)
+	state.LastUpdate = ae.now()
+	return state
+}
+
+// newEntityAnimationState creates animation state at rest at (x, y). It holds no
+// spring or clock of its own; whichever engine first updates it stamps LastUpdate
+// from its clock and supplies the spring.
+func newEntityAnimationState(x, y float64) *EntityAnimationState {
 	return &EntityAnimationState{
-		DisplayX:   x,
-		DisplayY:   y,
-		TargetX:    x,
-		TargetY:    y,
-		VelocityX:  0,
-		VelocityY:  0,
-		SpringX:    harmonica.NewSpring(harmonica.FPS(ae.TargetFPS), ae.SpringTension, ae.SpringDamping),
-		SpringY:    harmonica.NewSpring(harmonica.FPS(ae.TargetFPS), ae.SpringTension, ae.SpringDamping),
-		LastUpdate: time.Now(),
+		DisplayX: x,
+		DisplayY: y,
+		TargetX:  x,
+		TargetY:  y,
 	}
 }
 
@@ -82,13 +184,33 @@ func (eas *EntityAnimationState) SetTarget(x, y float64) {
 
 // UpdateAnimation advances the spring animation
 func (ae *AnimationEngine) UpdateAnimation(eas *EntityAnimationState) {
-	now := time.Now()
+	now := ae.now()
+	if eas.LastUpdate.IsZero() {
+		eas.LastUpdate = now
+	}
+	step := ae.frameStep(now, eas.LastUpdate)
 	eas.LastUpdate = now
+	ae.LastFrameTime, ae.FrameDelta = now, step
+	eas.Flash = countDown(eas.Flash, step)
+	eas.Squash = countDown(eas.Squash, step)
+
+	spring, smoothing := ae.currentSpring(step)
+	if smoothing && step == 0 {
+		return // No time has passed for the spring to move
+	}
+	if !smoothing {
+		// Springs off: show the physics position as it is
+		eas.IsAnimating = eas.DisplayX != eas.TargetX || eas.DisplayY != eas.TargetY
+		eas.DisplayX, eas.DisplayY = eas.TargetX, eas.TargetY
+		eas.VelocityX, eas.VelocityY = 0, 0
+		eas.recordTrail(ae.TrailLength)
+		return
+	}
 
 	// Update spring animations toward target positions
 	// Harmonica Update(position, velocity, target) returns new position and velocity
-	newX, newVX := eas.SpringX.Update(eas.DisplayX, eas.VelocityX, eas.TargetX)
-	newY, newVY := eas.SpringY.Update(eas.DisplayY, eas.VelocityY, eas.TargetY)
+	newX, newVX := spring.Update(eas.DisplayX, eas.VelocityX, eas.TargetX)
+	newY, newVY := spring.Update(eas.DisplayY, eas.VelocityY, eas.TargetY)
 
 	// Update display positions and velocities
 	eas.DisplayX = newX
@@ -104,6 +226,35 @@ func (ae *AnimationEngine) UpdateAnimation(eas *EntityAnimationState) {
 		abs(eas.VelocityX) < velocityThreshold && abs(eas.VelocityY) < velocityThreshold {
 		eas.IsAnimating = false
 	}
+
+	eas.recordTrail(ae.TrailLength)
+}
+
+// recordTrail remembers the display position when it enters a new cell.
+// Entities at rest shed one point per frame so their trail fades away.
+func (eas *EntityAnimationState) recordTrail(length int) {
+	snapped := eas.snapped
+	eas.snapped = false
+	if length <= 0 {
+		eas.Trail = nil
+		return
+	}
+
+	if !eas.IsAnimating && !snapped {
+		if len(eas.Trail) > 0 {
+			eas.Trail = eas.Trail[1:]
+		}
+		return
+	}
+
+	cellX, cellY := math.Floor(eas.DisplayX), math.Floor(eas.DisplayY)
+	if n := len(eas.Trail); n == 0 || math.Floor(eas.Trail[n-1][0]) != cellX || math.Floor(eas.Trail[n-1][1]) != cellY {
+		eas.Trail = append(eas.Trail, [2]float64{eas.DisplayX, eas.DisplayY})
+	}
+
+	if excess := len(eas.Trail) - length; excess > 0 {
+		eas.Trail = eas.Trail[excess:]
+	}
 }
 
 // GetDisplayPosition returns the current animated position
@@ -116,8 +267,11 @@ func (eas *EntityAnimationState) GetTarget() (float64, float64) {
 	return eas.TargetX, eas.TargetY
 }
 
-// SetInitialPosition sets both display and target to the same position (no animation)
+// SetInitialPosition sets both display and target to the same position (no animation).
+// Physics corrections such as bounces snap this way; the move still counts as motion
+// for the trail.
 func (eas *EntityAnimationState) SetInitialPosition(x, y float64) {
+	eas.snapped = eas.snapped || x != eas.DisplayX || y != eas.DisplayY
 	eas.DisplayX = x
 	eas.DisplayY = y
 	eas.TargetX = x
@@ -127,11 +281,27 @@ func (eas *EntityAnimationState) SetInitialPosition(x, y float64) {
 	eas.IsAnimating = false
 }
 
+// Teleport jumps to a position that was not reached by moving, such as a portal's
+// exit, and clears the trail so none is left behind at the old position
+func (eas *EntityAnimationState) Teleport(x, y float64) {
+	eas.SetInitialPosition(x, y)
+	eas.snapped = false
+	eas.Trail = nil
+}
+
 // IsStillAnimating returns whether the entity is still in motion
 func (eas *EntityAnimationState) IsStillAnimating() bool {
 	return eas.IsAnimating
 }
 
+// countDown lowers a remaining duration by step, stopping at zero
+func countDown(remaining, step time.Duration) time.Duration {
+	if remaining <= step {
+		return 0
+	}
+	return remaining - step
+}
+
 // Helper function for absolute value
 func abs(x float64) float64 {
 	if x < 0 {
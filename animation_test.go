@@ -1,8 +1,13 @@
 package main
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNewAnimationEngine(t *testing.T) {
@@ -133,18 +138,19 @@ func TestSetInitialPosition(t *testing.T) {
 }
 
 func TestAnimationConvergence(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
 	ae := NewAnimationEngine()
+	ae.Clock = clock.Now
 	eas := ae.NewEntityAnimationState(0.0, 0.0)
 	eas.SetTarget(1.0, 1.0) // Small target for faster convergence
 
 	// Simulate multiple animation frames
 	maxFrames := 1000
 	for i := 0; i < maxFrames; i++ {
+		// Advance one frame to simulate real timing
+		clock.Advance(time.Second / 60)
 		ae.UpdateAnimation(eas)
 
-		// Add small delay to simulate real timing
-		time.Sleep(time.Microsecond)
-
 		// Check if animation has converged
 		if !eas.IsAnimating {
 			t.Logf("Animation converged after %d frames", i+1)
@@ -160,3 +166,171 @@ func TestAnimationConvergence(t *testing.T) {
 
 	t.Error("Animation did not converge within reasonable time")
 }
+
+func TestSpringSettingsApplyToExistingEntities(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = clock.Now
+	sphere := NewSphere(0, 0, 1, "#00FF00") // Built before the settings change
+	sphere.UpdateAnimation(ae)
+	update := func() {
+		clock.Advance(time.Second / 60)
+		sphere.UpdateAnimation(ae)
+	}
+
+	// The same move under two presets, on the same entity's state
+	frameFrom := func(preset SpringPreset) float64 {
+		ae.ApplyPreset(preset)
+		sphere.AnimationState.SetInitialPosition(0, 0)
+		sphere.AnimationState.SetTarget(10, 0)
+		update()
+		x, _ := sphere.GetDisplayPosition()
+		return x
+	}
+	smooth := frameFrom(springPresets[1])
+	snappy := frameFrom(springPresets[0])
+	if snappy <= smooth {
+		t.Errorf("Expected the snappy preset to move further in one frame than smooth, got %.3f vs %.3f", snappy, smooth)
+	}
+
+	if x := frameFrom(springPresets[3]); x != 10 || !sphere.AnimationState.IsStillAnimating() {
+		t.Errorf("Expected no smoothing to jump straight to the target, got %.3f", x)
+	}
+	update()
+	if sphere.AnimationState.IsStillAnimating() {
+		t.Error("Expected an unsmoothed entity to stop animating once it stops moving")
+	}
+
+	// Wobbly springs overshoot the target
+	ae.ApplyPreset(springPresets[2])
+	sphere.AnimationState.SetInitialPosition(0, 0)
+	sphere.AnimationState.SetTarget(10, 0)
+	peak := 0.0
+	for i := 0; i < 120; i++ {
+		update()
+		x, _ := sphere.GetDisplayPosition()
+		peak = math.Max(peak, x)
+	}
+	if peak <= 10 {
+		t.Errorf("Expected the wobbly preset to overshoot, peaked at %.3f", peak)
+	}
+}
+
+func TestCycleSpringPreset(t *testing.T) {
+	model := initialModel()
+	if model.animationEngine.SpringTension != springPresets[defaultSpringPreset].Tension {
+		t.Fatal("Expected the model to start on the default preset")
+	}
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	model = updatedModel.(Model)
+	wobbly := springPresets[defaultSpringPreset+1]
+	if model.animationEngine.SpringTension != wobbly.Tension || model.animationEngine.SpringDamping != wobbly.Damping {
+		t.Errorf("Expected d to switch to the %s preset", wobbly.Name)
+	}
+
+	// The control panel button cycles too and shows the preset
+	updatedModel, _ = model.handleButtonAction(SpringsAction)
+	model = updatedModel.(Model)
+	if !strings.Contains(model.controlPanel.View(), "Springs: none") {
+		t.Error("Expected the springs button to show the selected preset")
+	}
+}
+
+// fakeClock is a manually advanced clock for AnimationEngine.Clock
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestAnimationStepsByElapsedTime(t *testing.T) {
+	// moveAfter animates 0 → 10 with updates the given time apart
+	moveAfter := func(steps ...time.Duration) float64 {
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		ae := NewAnimationEngine()
+		ae.Clock = clock.Now
+		eas := ae.NewEntityAnimationState(0, 0)
+		eas.SetTarget(10, 0)
+		for _, step := range steps {
+			clock.Advance(step)
+			ae.UpdateAnimation(eas)
+		}
+		return eas.DisplayX
+	}
+	frame := 20 * time.Millisecond
+
+	// A lagging tick catches up: one 60ms update lands where three 20ms ones do
+	if steady, lagged := moveAfter(frame, frame, frame), moveAfter(3*frame); math.Abs(steady-lagged) > 1e-9 {
+		t.Errorf("Expected the same position whatever the tick rate, got %.6f vs %.6f", steady, lagged)
+	}
+
+	// A long stall is clamped instead of jumping to the end
+	if stalled, clamped := moveAfter(5*time.Second), moveAfter(100*time.Millisecond); stalled != clamped {
+		t.Errorf("Expected a stall to advance at most MaxFrameDelta, got %.6f vs %.6f", stalled, clamped)
+	}
+
+	// Updates closer together than a frame advance only the time that passed, and
+	// an extra update in the same instant advances nothing
+	if burst, single := moveAfter(time.Millisecond), moveAfter(time.Second/60); burst <= 0 || burst >= single {
+		t.Errorf("Expected a burst update to advance less than a frame, got %.6f vs %.6f", burst, single)
+	}
+	if forced, single := moveAfter(frame, 0), moveAfter(frame); forced != single {
+		t.Errorf("Expected a repeated update not to advance, got %.6f vs %.6f", forced, single)
+	}
+
+	// Steps are not rounded to whole milliseconds
+	if exact, rounded := moveAfter(time.Second/60), moveAfter(17*time.Millisecond); exact == rounded {
+		t.Error("Expected a 16.67ms frame to step by 16.67ms")
+	}
+
+	// Entities built without the engine start on its clock at their first update
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = clock.Now
+	sphere := NewSphere(0, 0, 1, lipgloss.Color("#FF0000"))
+	sphere.AnimationState.SetTarget(10, 0)
+	sphere.UpdateAnimation(ae)
+	clock.Advance(frame)
+	sphere.UpdateAnimation(ae)
+	if x, _ := sphere.GetDisplayPosition(); x != moveAfter(frame) {
+		t.Errorf("Expected the sphere to step by one frame of the engine's clock, got %.6f", x)
+	}
+}
+
+func TestUpdateEntitiesSharesOneStep(t *testing.T) {
+	// Every read of this clock is a microsecond later than the one before
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = func() time.Time {
+		clock.Advance(time.Microsecond)
+		return clock.Now()
+	}
+	start := clock.Now()
+	var entities []Entity
+	for i := 0; i < 5; i++ {
+		sphere := NewSphere(float64(i), 0, 1, lipgloss.Color("#FF0000"))
+		sphere.AnimationState = ae.NewEntityAnimationState(float64(i), 0)
+		sphere.AnimationState.LastUpdate = start // Last updated together in the previous tick
+		sphere.AnimationState.SetTarget(float64(i)+10, 0)
+		entities = append(entities, sphere)
+	}
+
+	clock.Advance(time.Second / 60)
+	ae.UpdateEntities(entities)
+	step := ae.springSettings[2]
+	for _, entity := range entities {
+		state := entity.GetAnimationState()
+		if !state.LastUpdate.Equal(ae.LastFrameTime) {
+			t.Fatal("Expected every entity in a tick to be stepped to the same instant")
+		}
+		if moved := state.DisplayX - (state.TargetX - 10); math.Abs(moved-entities[0].GetAnimationState().DisplayX) > 1e-12 {
+			t.Errorf("Expected every entity to move by the same step, got %.9f", moved)
+		}
+	}
+	if step != ae.FrameDelta.Seconds() {
+		t.Errorf("Expected one spring built for the tick's step, got %g for %g", step, ae.FrameDelta.Seconds())
+	}
+	if ae.now().Equal(ae.LastFrameTime) {
+		t.Error("Expected the clock to be read again after the tick")
+	}
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// Behavior is per-entity logic run once per simulation tick, before physics
+type Behavior interface {
+	Update(entity Entity, world World, deltaTime float64)
+}
+
+// World is the read-only view of the simulation available to behaviors
+type World interface {
+	// Entities returns a snapshot of every entity in the simulation
+	Entities() []Entity
+	// QueryRadius returns the entities whose centers lie within radius of (x, y)
+	QueryRadius(x, y, radius float64) []Entity
+	// Bounds returns the physics walls
+	Bounds() (minX, minY, maxX, maxY float64)
+	// Cursor returns the mouse position in simulation coordinates, if it is over the simulation
+	Cursor() (x, y float64, ok bool)
+}
+
+// behaviorHost is implemented by entities that can carry a behavior
+type behaviorHost interface {
+	GetBehavior() Behavior
+}
+
+// simulationWorld adapts the entity manager and physics engine to the World interface
+type simulationWorld struct {
+	entityManager *EntityManager
+	physicsEngine *PhysicsEngine
+
+	cursorX, cursorY float64
+	hasCursor        bool
+}
+
+func (w *simulationWorld) Entities() []Entity {
+	return w.entityManager.GetEntities()
+}
+
+func (w *simulationWorld) QueryRadius(x, y, radius float64) []Entity {
+	return w.entityManager.QueryRadius(x, y, radius)
+}
+
+func (w *simulationWorld) Bounds() (minX, minY, maxX, maxY float64) {
+	return w.physicsEngine.MinX, w.physicsEngine.MinY, w.physicsEngine.MaxX, w.physicsEngine.MaxY
+}
+
+func (w *simulationWorld) Cursor() (x, y float64, ok bool) {
+	return w.cursorX, w.cursorY, w.hasCursor
+}
+
+// ApplyBehaviors runs the behavior attached to each entity
+func ApplyBehaviors(entities []Entity, world World, deltaTime float64) {
+	if deltaTime <= 0 {
+		return
+	}
+	for _, entity := range entities {
+		host, ok := entity.(behaviorHost)
+		if !ok {
+			continue
+		}
+		if behavior := host.GetBehavior(); behavior != nil {
+			behavior.Update(entity, world, deltaTime)
+		}
+	}
+}
+
+// steerVelocityToward eases an entity's velocity toward a desired velocity.
+// responsiveness is the fraction of the difference closed per second.
+func steerVelocityToward(entity Entity, desiredVX, desiredVY, responsiveness, deltaTime float64) {
+	blend := math.Min(1, responsiveness*deltaTime)
+	vx, vy := entity.GetVelocity()
+	entity.SetVelocity(vx+(desiredVX-vx)*blend, vy+(desiredVY-vy)*blend)
+}
+
+// WanderBehavior drifts in a slowly changing random direction
+type WanderBehavior struct {
+	Speed          float64 // Cruise speed
+	Jitter         float64 // How quickly the direction changes (radians per second)
+	Responsiveness float64
+	angle          float64
+}
+
+// NewWanderBehavior creates a wander behavior with a random starting direction
+func NewWanderBehavior(speed, jitter float64) *WanderBehavior {
+	return &WanderBehavior{
+		Speed:          speed,
+		Jitter:         jitter,
+		Responsiveness: 2.0,
+		angle:          rand.Float64() * 2 * math.Pi,
+	}
+}
+
+func (b *WanderBehavior) Update(entity Entity, world World, deltaTime float64) {
+	b.angle += (rand.Float64()*2 - 1) * b.Jitter * deltaTime
+	steerVelocityToward(entity, math.Cos(b.angle)*b.Speed, math.Sin(b.angle)*b.Speed, b.Responsiveness, deltaTime)
+}
+
+// SeekBehavior moves toward a fixed point, slowing down on arrival
+type SeekBehavior struct {
+	TargetX, TargetY float64
+	Speed            float64
+	ArriveRadius     float64 // Distance at which the entity starts braking
+	Responsiveness   float64
+}
+
+// NewSeekBehavior creates a seek behavior targeting (x, y)
+func NewSeekBehavior(x, y, speed float64) *SeekBehavior {
+	return &SeekBehavior{TargetX: x, TargetY: y, Speed: speed, ArriveRadius: 3.0, Responsiveness: 4.0}
+}
+
+func (b *SeekBehavior) Update(entity Entity, world World, deltaTime float64) {
+	x, y := entity.GetPosition()
+	dx, dy := b.TargetX-x, b.TargetY-y
+	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance == 0 {
+		steerVelocityToward(entity, 0, 0, b.Responsiveness, deltaTime)
+		return
+	}
+
+	speed := b.Speed
+	if b.ArriveRadius > 0 && distance < b.ArriveRadius {
+		speed *= distance / b.ArriveRadius
+	}
+	steerVelocityToward(entity, dx/distance*speed, dy/distance*speed, b.Responsiveness, deltaTime)
+}
+
+// FleeCursorBehavior runs away from the mouse cursor when it comes close
+type FleeCursorBehavior struct {
+	Radius         float64 // Panic distance
+	Speed          float64
+	Responsiveness float64
+}
+
+// NewFleeCursorBehavior creates a flee behavior with the given panic radius
+func NewFleeCursorBehavior(radius, speed float64) *FleeCursorBehavior {
+	return &FleeCursorBehavior{Radius: radius, Speed: speed, Responsiveness: 6.0}
+}
+
+func (b *FleeCursorBehavior) Update(entity Entity, world World, deltaTime float64) {
+	cx, cy, ok := world.Cursor()
+	if !ok {
+		return
+	}
+	x, y := entity.GetPosition()
+	dx, dy := x-cx, y-cy
+	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance >= b.Radius {
+		return
+	}
+	if distance == 0 {
+		dx, dy, distance = rand.Float64()-0.5, rand.Float64()-0.5, 1
+	}
+
+	// Flee harder the closer the cursor is
+	urgency := 1 - distance/b.Radius
+	steerVelocityToward(entity, dx/distance*b.Speed, dy/distance*b.Speed, b.Responsiveness*urgency, deltaTime)
+}
+
+// OrbitBehavior circles a center point at a fixed radius
+type OrbitBehavior struct {
+	CenterX, CenterY float64
+	Radius           float64
+	Speed            float64 // Tangential speed; negative orbits counter-clockwise
+	Responsiveness   float64
+}
+
+// NewOrbitBehavior creates an orbit around (cx, cy)
+func NewOrbitBehavior(cx, cy, radius, speed float64) *OrbitBehavior {
+	return &OrbitBehavior{CenterX: cx, CenterY: cy, Radius: radius, Speed: speed, Responsiveness: 4.0}
+}
+
+func (b *OrbitBehavior) Update(entity Entity, world World, deltaTime float64) {
+	x, y := entity.GetPosition()
+	rx, ry := x-b.CenterX, y-b.CenterY
+	distance := math.Sqrt(rx*rx + ry*ry)
+	if distance == 0 {
+		rx, ry, distance = 1, 0, 1
+	}
+	ux, uy := rx/distance, ry/distance
+
+	// Tangential motion plus a radial correction back onto the circle
+	correction := b.Radius - distance
+	desiredVX := -uy*b.Speed + ux*correction
+	desiredVY := ux*b.Speed + uy*correction
+	steerVelocityToward(entity, desiredVX, desiredVY, b.Responsiveness, deltaTime)
+}
+
+// PatrolBehavior visits a list of waypoints in a loop
+type PatrolBehavior struct {
+	Points         [][2]float64
+	Speed          float64
+	ReachRadius    float64 // Distance at which a waypoint counts as visited
+	Responsiveness float64
+	current        int
+}
+
+// NewPatrolBehavior creates a patrol over the given waypoints
+func NewPatrolBehavior(points [][2]float64, speed float64) *PatrolBehavior {
+	return &PatrolBehavior{Points: points, Speed: speed, ReachRadius: 1.0, Responsiveness: 4.0}
+}
+
+func (b *PatrolBehavior) Update(entity Entity, world World, deltaTime float64) {
+	if len(b.Points) == 0 {
+		return
+	}
+	x, y := entity.GetPosition()
+	target := b.Points[b.current]
+	dx, dy := target[0]-x, target[1]-y
+	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance < b.ReachRadius {
+		b.current = (b.current + 1) % len(b.Points)
+		return
+	}
+	steerVelocityToward(entity, dx/distance*b.Speed, dy/distance*b.Speed, b.Responsiveness, deltaTime)
+}
+
+// CurrentWaypoint returns the index of the waypoint being approached
+func (b *PatrolBehavior) CurrentWaypoint() int {
+	return b.current
+}
+
+// CompositeBehavior runs several behaviors in order every tick
+type CompositeBehavior struct {
+	Children []Behavior
+}
+
+func (b *CompositeBehavior) Update(entity Entity, world World, deltaTime float64) {
+	for _, child := range b.Children {
+		child.Update(entity, world, deltaTime)
+	}
+}
+
+// Behavior type names used by BehaviorSpec
+const (
+	WanderBehaviorName     = "wander"
+	SeekBehaviorName       = "seek"
+	FleeCursorBehaviorName = "flee_cursor"
+	OrbitBehaviorName      = "orbit"
+	PatrolBehaviorName     = "patrol"
+	CompositeBehaviorName  = "all"
+)
+
+// BehaviorSpec is the declarative description of a behavior.
+// When Relative is set, Target and Points are fractions (0-1) of the simulation bounds.
+type BehaviorSpec struct {
+	Type     string         `json:"type"`
+	Target   []float64      `json:"target,omitempty"`
+	Points   [][]float64    `json:"points,omitempty"`
+	Radius   float64        `json:"radius,omitempty"`
+	Speed    float64        `json:"speed,omitempty"`
+	Jitter   float64        `json:"jitter,omitempty"`
+	Relative bool           `json:"relative,omitempty"`
+	Children []BehaviorSpec `json:"children,omitempty"`
+}
+
+// behaviorConfig is the top-level layout of a behavior config file
+type behaviorConfig struct {
+	Behaviors map[string]BehaviorSpec `json:"behaviors"`
+}
+
+// Build creates a fresh behavior instance for one entity.
+// Behaviors keep per-entity state, so every entity needs its own instance.
+func (s BehaviorSpec) Build(minX, minY, maxX, maxY float64) (Behavior, error) {
+	resolve := func(p []float64) ([2]float64, error) {
+		if len(p) != 2 {
+			return [2]float64{}, fmt.Errorf("%s: point must have 2 coordinates, got %d", s.Type, len(p))
+		}
+		if s.Relative {
+			return [2]float64{minX + p[0]*(maxX-minX), minY + p[1]*(maxY-minY)}, nil
+		}
+		return [2]float64{p[0], p[1]}, nil
+	}
+	speed := s.Speed
+	if speed <= 0 {
+		speed = 6.0
+	}
+
+	switch s.Type {
+	case WanderBehaviorName:
+		jitter := s.Jitter
+		if jitter <= 0 {
+			jitter = 3.0
+		}
+		return NewWanderBehavior(speed, jitter), nil
+
+	case SeekBehaviorName:
+		target, err := resolve(s.Target)
+		if err != nil {
+			return nil, err
+		}
+		b := NewSeekBehavior(target[0], target[1], speed)
+		if s.Radius > 0 {
+			b.ArriveRadius = s.Radius
+		}
+		return b, nil
+
+	case FleeCursorBehaviorName:
+		radius := s.Radius
+		if radius <= 0 {
+			radius = 6.0
+		}
+		return NewFleeCursorBehavior(radius, speed), nil
+
+	case OrbitBehaviorName:
+		center, err := resolve(s.Target)
+		if err != nil {
+			return nil, err
+		}
+		radius := s.Radius
+		if radius <= 0 {
+			radius = 5.0
+		}
+		return NewOrbitBehavior(center[0], center[1], radius, speed), nil
+
+	case PatrolBehaviorName:
+		if len(s.Points) == 0 {
+			return nil, fmt.Errorf("%s: at least one point is required", s.Type)
+		}
+		points := make([][2]float64, 0, len(s.Points))
+		for _, p := range s.Points {
+			point, err := resolve(p)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, point)
+		}
+		return NewPatrolBehavior(points, speed), nil
+
+	case CompositeBehaviorName:
+		composite := &CompositeBehavior{}
+		for _, childSpec := range s.Children {
+			child, err := childSpec.Build(minX, minY, maxX, maxY)
+			if err != nil {
+				return nil, err
+			}
+			composite.Children = append(composite.Children, child)
+		}
+		return composite, nil
+	}
+
+	return nil, fmt.Errorf("unknown behavior type %q", s.Type)
+}
+
+// LoadBehaviorSpecs parses a JSON behavior config of the form
+//
+//	{"behaviors": {"guard": {"type": "patrol", "points": [[0.1, 0.5], [0.9, 0.5]], "relative": true}}}
+//
+// Every spec is validated by building it once against unit bounds.
+func LoadBehaviorSpecs(r io.Reader) (map[string]BehaviorSpec, error) {
+	var config behaviorConfig
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("parsing behavior config: %w", err)
+	}
+	for name, spec := range config.Behaviors {
+		if _, err := spec.Build(0, 0, 1, 1); err != nil {
+			return nil, fmt.Errorf("behavior %q: %w", name, err)
+		}
+	}
+	return config.Behaviors, nil
+}
+
+// LoadBehaviorFile reads a behavior config from disk
+func LoadBehaviorFile(path string) (map[string]BehaviorSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBehaviorSpecs(f)
+}
+
+// builtinBehaviorNames lists the behaviors cycled with the v key, in order.
+// An empty name means no behavior.
+var builtinBehaviorNames = []string{"", WanderBehaviorName, SeekBehaviorName, FleeCursorBehaviorName, OrbitBehaviorName, PatrolBehaviorName}
+
+// builtinBehaviorSpecs returns the default presets, expressed relative to the simulation bounds
+func builtinBehaviorSpecs() map[string]BehaviorSpec {
+	return map[string]BehaviorSpec{
+		WanderBehaviorName:     {Type: WanderBehaviorName},
+		SeekBehaviorName:       {Type: SeekBehaviorName, Target: []float64{0.5, 0.5}, Relative: true},
+		FleeCursorBehaviorName: {Type: FleeCursorBehaviorName, Speed: 15},
+		OrbitBehaviorName:      {Type: OrbitBehaviorName, Target: []float64{0.5, 0.5}, Radius: 5, Relative: true},
+		PatrolBehaviorName: {Type: PatrolBehaviorName, Relative: true, Points: [][]float64{
+			{0.2, 0.3}, {0.8, 0.3}, {0.8, 0.7}, {0.2, 0.7},
+		}},
+	}
+}
+
+// sortedBehaviorNames returns the names of loaded specs in a stable order
+func sortedBehaviorNames(specs map[string]BehaviorSpec) []string {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
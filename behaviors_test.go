@@ -0,0 +1,184 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fakeWorld is a fixed World for driving behaviors in tests
+type fakeWorld struct {
+	entities         []Entity
+	cursorX, cursorY float64
+	hasCursor        bool
+}
+
+func (w *fakeWorld) Entities() []Entity                       { return w.entities }
+func (w *fakeWorld) Bounds() (minX, minY, maxX, maxY float64) { return 0, 0, 100, 100 }
+func (w *fakeWorld) Cursor() (x, y float64, ok bool)          { return w.cursorX, w.cursorY, w.hasCursor }
+func (w *fakeWorld) QueryRadius(x, y, radius float64) []Entity {
+	return queryRadius(newSpatialHash(w.entities), x, y, radius)
+}
+
+func TestSeekBehavior(t *testing.T) {
+	sphere := NewSphere(10.0, 10.0, 1, lipgloss.Color("32"))
+	sphere.SetBehavior(NewSeekBehavior(30.0, 10.0, 6.0))
+
+	ApplyBehaviors([]Entity{sphere}, &fakeWorld{}, 0.1)
+
+	vx, vy := sphere.GetVelocity()
+	if vx <= 0 {
+		t.Errorf("Expected velocity toward target (positive vx), got %.3f", vx)
+	}
+	if math.Abs(vy) > 1e-9 {
+		t.Errorf("Expected no vertical steering, got vy=%.3f", vy)
+	}
+}
+
+func TestFleeCursorBehavior(t *testing.T) {
+	sphere := NewSphere(10.0, 10.0, 1, lipgloss.Color("32"))
+	flee := NewFleeCursorBehavior(5.0, 10.0)
+
+	// No cursor: nothing happens
+	flee.Update(sphere, &fakeWorld{}, 0.1)
+	if vx, vy := sphere.GetVelocity(); vx != 0 || vy != 0 {
+		t.Errorf("Expected no reaction without a cursor, got (%.2f, %.2f)", vx, vy)
+	}
+
+	// Cursor just left of the entity: flee to the right
+	flee.Update(sphere, &fakeWorld{cursorX: 8.0, cursorY: 10.0, hasCursor: true}, 0.1)
+	if vx, _ := sphere.GetVelocity(); vx <= 0 {
+		t.Errorf("Expected entity to flee right, got vx=%.3f", vx)
+	}
+}
+
+func TestOrbitBehavior(t *testing.T) {
+	sphere := NewSphere(15.0, 10.0, 1, lipgloss.Color("32"))
+	NewOrbitBehavior(10.0, 10.0, 5.0, 4.0).Update(sphere, &fakeWorld{}, 0.1)
+
+	// On the circle east of the center, clockwise (screen) motion is downward
+	vx, vy := sphere.GetVelocity()
+	if vy <= 0 || math.Abs(vx) > 1e-9 {
+		t.Errorf("Expected purely tangential velocity, got (%.3f, %.3f)", vx, vy)
+	}
+}
+
+func TestPatrolBehaviorAdvancesWaypoints(t *testing.T) {
+	sphere := NewSphere(10.0, 10.0, 1, lipgloss.Color("32"))
+	patrol := NewPatrolBehavior([][2]float64{{10.2, 10.0}, {20.0, 10.0}}, 5.0)
+
+	patrol.Update(sphere, &fakeWorld{}, 0.1)
+	if patrol.CurrentWaypoint() != 1 {
+		t.Errorf("Expected patrol to advance to waypoint 1, got %d", patrol.CurrentWaypoint())
+	}
+
+	patrol.Update(sphere, &fakeWorld{}, 0.1)
+	if vx, _ := sphere.GetVelocity(); vx <= 0 {
+		t.Errorf("Expected movement toward next waypoint, got vx=%.3f", vx)
+	}
+}
+
+func TestLoadBehaviorSpecs(t *testing.T) {
+	config := `{
+		"behaviors": {
+			"guard": {"type": "patrol", "points": [[0, 0.5], [1, 0.5]], "relative": true, "speed": 4},
+			"skittish": {"type": "all", "children": [{"type": "wander"}, {"type": "flee_cursor", "radius": 8}]}
+		}
+	}`
+
+	specs, err := LoadBehaviorSpecs(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("Expected 2 specs, got %d", len(specs))
+	}
+
+	behavior, err := specs["guard"].Build(0, 0, 40, 20)
+	if err != nil {
+		t.Fatalf("Unexpected build error: %v", err)
+	}
+	patrol, ok := behavior.(*PatrolBehavior)
+	if !ok {
+		t.Fatalf("Expected *PatrolBehavior, got %T", behavior)
+	}
+	if patrol.Points[1] != [2]float64{40, 10} {
+		t.Errorf("Expected relative point resolved to (40, 10), got %v", patrol.Points[1])
+	}
+
+	behavior, _ = specs["skittish"].Build(0, 0, 40, 20)
+	if composite, ok := behavior.(*CompositeBehavior); !ok || len(composite.Children) != 2 {
+		t.Errorf("Expected composite with 2 children, got %T", behavior)
+	}
+}
+
+func TestLoadBehaviorSpecsErrors(t *testing.T) {
+	configs := []string{
+		`{"behaviors": {"x": {"type": "teleport"}}}`,
+		`{"behaviors": {"x": {"type": "seek", "target": [1]}}}`,
+		`{"behaviors": {"x": {"type": "patrol"}}}`,
+		`{"behaviors": {"x": {"type": "wander", "colour": "red"}}}`,
+		`not json`,
+	}
+	for _, config := range configs {
+		if _, err := LoadBehaviorSpecs(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected error for config %s", config)
+		}
+	}
+}
+
+func TestModelBehaviorCycling(t *testing.T) {
+	model := initialModel()
+	model.termWidth = 80
+	model.termHeight = 24
+	model.updatePaneDimensions()
+	model.ready = true
+
+	// Select wander and add a sphere
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	model = updatedModel.(Model)
+	if model.selectedBehaviorName() != WanderBehaviorName {
+		t.Fatalf("Expected wander to be selected, got %q", model.selectedBehaviorName())
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = updatedModel.(Model)
+
+	entities := model.entityManager.GetEntities()
+	if len(entities) != 1 {
+		t.Fatalf("Expected 1 entity, got %d", len(entities))
+	}
+	if _, ok := entities[0].(*Sphere).GetBehavior().(*WanderBehavior); !ok {
+		t.Errorf("Expected new sphere to carry a wander behavior")
+	}
+
+	// Ticking runs the behavior without errors
+	updatedModel, _ = model.Update(tickMsg(time.Now()))
+	model = updatedModel.(Model)
+}
+
+func TestModelTracksCursor(t *testing.T) {
+	model := initialModel()
+	model.termWidth = 80
+	model.termHeight = 24
+	model.updatePaneDimensions()
+	model.ready = true
+
+	updatedModel, _ := model.Update(tea.MouseMsg{X: SimGridOriginX + 5, Y: SimGridOriginY + 2, Action: tea.MouseActionMotion})
+	model = updatedModel.(Model)
+
+	x, y, ok := model.world().Cursor()
+	if !ok || x != 5.5 || y != 2.5 {
+		t.Errorf("Expected cursor at (5.5, 2.5), got (%.1f, %.1f, %v)", x, y, ok)
+	}
+
+	updatedModel, _ = model.Update(tea.MouseMsg{X: 0, Y: 0, Action: tea.MouseActionMotion})
+	model = updatedModel.(Model)
+	if _, _, ok := model.world().Cursor(); ok {
+		t.Error("Expected cursor outside the simulation grid to be reported as absent")
+	}
+}
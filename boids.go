@@ -0,0 +1,297 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BoidType identifies flocking entities
+const BoidType EntityType = "boid"
+
+// Heading glyphs ordered clockwise on screen starting from east (+X).
+// Terminal rows grow downward, so a positive angle points down the screen.
+var (
+	boidHeadingGlyphs      = []string{"→", "↘", "↓", "↙", "←", "↖", "↑", "↗"}
+	boidLargeHeadingGlyphs = []string{"⮕", "⬊", "⬇", "⬋", "⬅", "⬉", "⬆", "⬈"}
+)
+
+// BoidParams holds the tunable steering weights for a boid
+type BoidParams struct {
+	SeparationWeight float64 // Push away from crowding neighbors
+	AlignmentWeight  float64 // Match the average heading of nearby boids
+	CohesionWeight   float64 // Steer toward the local center of the flock
+	AvoidanceWeight  float64 // Steer away from walls
+
+	PerceptionRadius float64 // How far a boid can see its neighbors
+	SeparationRadius float64 // Gap between collision bounds that triggers separation
+	WallMargin       float64 // Distance from a wall at which avoidance kicks in
+
+	MinSpeed float64 // Boids never hover; they keep cruising at least this fast
+	MaxSpeed float64 // Cruise speed cap
+	MaxForce float64 // Maximum steering change per second
+}
+
+// DefaultBoidParams returns weights that give a loose, lively flock in a terminal grid
+func DefaultBoidParams() BoidParams {
+	return BoidParams{
+		SeparationWeight: 1.8,
+		AlignmentWeight:  1.0,
+		CohesionWeight:   0.8,
+		AvoidanceWeight:  2.5,
+		PerceptionRadius: 8.0,
+		SeparationRadius: 1.5,
+		WallMargin:       3.0,
+		MinSpeed:         4.0,
+		MaxSpeed:         12.0,
+		MaxForce:         30.0,
+	}
+}
+
+// Boid is a self-propelled entity that steers by separation, alignment and cohesion
+type Boid struct {
+	BaseEntity
+	Params  BoidParams
+	Heading float64 // Direction of travel in radians (screen coordinates)
+}
+
+func init() {
+	// Boids draw a heading arrow in Render, so they register no size glyphs
+	mustRegisterEntityType(EntityTypeInfo{
+		Type:     BoidType,
+		Name:     "Boid",
+		Icon:     "➤",
+		Key:      "o",
+		Material: FeatherMaterial,
+		Shape:    ShapeEllipse,
+		New: func(x, y float64, size int, color lipgloss.Color) Entity {
+			return NewBoid(x, y, size, color)
+		},
+		Order: 30,
+	})
+}
+
+// NewBoid creates a new boid with a random initial heading
+func NewBoid(x, y float64, size int, color lipgloss.Color) *Boid {
+	// Validate and sanitize size input
+	if size < 0 {
+		size = 1
+	}
+
+	animState := newEntityAnimationState(x, y)
+
+	params := DefaultBoidParams()
+	heading := rand.Float64() * 2 * math.Pi
+	speed := (params.MinSpeed + params.MaxSpeed) / 2
+
+	return &Boid{
+		BaseEntity: BaseEntity{
+			ID:             generateID("boid"),
+			X:              x,
+			Y:              y,
+			VX:             math.Cos(heading) * speed,
+			VY:             math.Sin(heading) * speed,
+			Size:           size,
+			Color:          color,
+			Symbol:         boidHeadingGlyphs[0],
+			Type:           BoidType,
+			Mass:           massFor(BoidType, size), // Boids are light and agile
+			AnimationState: animState,
+
+			CollisionCategory: LayerDefault,
+			CollisionMask:     LayerAll,
+		},
+		Params:  params,
+		Heading: heading,
+	}
+}
+
+// IgnoresGravity reports that boids propel themselves and are not pulled down
+func (b *Boid) IgnoresGravity() bool {
+	return true
+}
+
+// Steer applies the flocking rules for one time step.
+// neighbors may include the boid itself and non-boid entities; only other boids
+// contribute to alignment and cohesion, while every entity is kept at a distance.
+func (b *Boid) Steer(neighbors []Entity, minX, minY, maxX, maxY, deltaTime float64) {
+	if deltaTime <= 0 {
+		return
+	}
+
+	p := b.Params
+	_, _, w, h := b.GetBounds()
+	radius := math.Max(w, h) / 2
+
+	var sepX, sepY float64
+	var alignX, alignY float64
+	var centerX, centerY float64
+	flockmates := 0
+
+	for _, other := range neighbors {
+		if other == Entity(b) {
+			continue
+		}
+
+		ox, oy := other.GetPosition()
+		dx := b.X - ox
+		dy := b.Y - oy
+		distance := math.Sqrt(dx*dx + dy*dy)
+		if distance > p.PerceptionRadius {
+			continue
+		}
+
+		// Separation works on the gap between collision bounds so large
+		// entities are given proportionally more room
+		_, _, ow, oh := other.GetBounds()
+		gap := distance - radius - math.Max(ow, oh)/2
+		if gap < p.SeparationRadius {
+			if distance == 0 {
+				dx, dy, distance = rand.Float64()-0.5, rand.Float64()-0.5, 1
+			}
+			strength := (p.SeparationRadius - gap) / p.SeparationRadius
+			sepX += dx / distance * strength
+			sepY += dy / distance * strength
+		}
+
+		if other.GetType() != BoidType {
+			continue
+		}
+		ovx, ovy := other.GetVelocity()
+		alignX += ovx
+		alignY += ovy
+		centerX += ox
+		centerY += oy
+		flockmates++
+	}
+
+	var steerX, steerY float64
+
+	if sepX != 0 || sepY != 0 {
+		fx, fy := b.steerToward(sepX, sepY)
+		steerX += fx * p.SeparationWeight
+		steerY += fy * p.SeparationWeight
+	}
+
+	if flockmates > 0 {
+		fx, fy := b.steerToward(alignX, alignY)
+		steerX += fx * p.AlignmentWeight
+		steerY += fy * p.AlignmentWeight
+
+		n := float64(flockmates)
+		fx, fy = b.steerToward(centerX/n-b.X, centerY/n-b.Y)
+		steerX += fx * p.CohesionWeight
+		steerY += fy * p.CohesionWeight
+	}
+
+	// Wall avoidance measures from the edges of the collision bounds so the
+	// boid turns before its body reaches the wall
+	var wallX, wallY float64
+	if gap := (b.X - radius) - minX; gap < p.WallMargin {
+		wallX += (p.WallMargin - gap) / p.WallMargin
+	}
+	if gap := maxX - (b.X + radius); gap < p.WallMargin {
+		wallX -= (p.WallMargin - gap) / p.WallMargin
+	}
+	if gap := (b.Y - radius) - minY; gap < p.WallMargin {
+		wallY += (p.WallMargin - gap) / p.WallMargin
+	}
+	if gap := maxY - (b.Y + radius); gap < p.WallMargin {
+		wallY -= (p.WallMargin - gap) / p.WallMargin
+	}
+	if wallX != 0 || wallY != 0 {
+		fx, fy := b.steerToward(wallX, wallY)
+		steerX += fx * p.AvoidanceWeight
+		steerY += fy * p.AvoidanceWeight
+	}
+
+	steerX, steerY = clampMagnitude(steerX, steerY, p.MaxForce)
+	vx := b.VX + steerX*deltaTime
+	vy := b.VY + steerY*deltaTime
+
+	// Keep cruising between the minimum and maximum speed
+	speed := math.Sqrt(vx*vx + vy*vy)
+	switch {
+	case speed == 0:
+		vx, vy = math.Cos(b.Heading)*p.MinSpeed, math.Sin(b.Heading)*p.MinSpeed
+	case speed < p.MinSpeed:
+		vx, vy = vx/speed*p.MinSpeed, vy/speed*p.MinSpeed
+	case speed > p.MaxSpeed:
+		vx, vy = vx/speed*p.MaxSpeed, vy/speed*p.MaxSpeed
+	}
+
+	b.SetVelocity(vx, vy)
+}
+
+// steerToward returns the Reynolds steering force toward a desired direction
+func (b *Boid) steerToward(dx, dy float64) (float64, float64) {
+	length := math.Sqrt(dx*dx + dy*dy)
+	if length == 0 {
+		return 0, 0
+	}
+	desiredX := dx / length * b.Params.MaxSpeed
+	desiredY := dy / length * b.Params.MaxSpeed
+	return clampMagnitude(desiredX-b.VX, desiredY-b.VY, b.Params.MaxForce)
+}
+
+// Update moves the boid and turns its heading to follow its velocity
+func (b *Boid) Update(deltaTime float64) {
+	b.BaseEntity.Update(deltaTime)
+	if b.VX != 0 || b.VY != 0 {
+		b.Heading = math.Atan2(b.VY, b.VX)
+	}
+	b.Symbol = boidHeadingGlyphs[headingIndex(b.Heading)]
+}
+
+// Render draws the boid as an arrow pointing along its heading
+func (b *Boid) Render() Cell {
+	glyphs := boidHeadingGlyphs
+	if b.Size >= 3 {
+		glyphs = boidLargeHeadingGlyphs
+	}
+	return Cell{Glyph: glyphs[headingIndex(b.Heading)], Color: b.Color, Bold: true, Faint: b.IsGhost()}
+}
+
+// RenderCells draws large boids as a rounded body with the heading arrow in the middle
+func (b *Boid) RenderCells() [][]Cell {
+	rows := b.BaseEntity.RenderCells()
+	width, height := len(rows[0]), len(rows)
+	rows[(height-1)/2][width/2] = b.Render()
+	return rows
+}
+
+// headingIndex maps an angle in radians to one of eight compass glyphs
+func headingIndex(angle float64) int {
+	index := int(math.Round(angle/(math.Pi/4))) % 8
+	if index < 0 {
+		index += 8
+	}
+	return index
+}
+
+// clampMagnitude scales a vector down so its length does not exceed limit
+func clampMagnitude(x, y, limit float64) (float64, float64) {
+	length := math.Sqrt(x*x + y*y)
+	if length > limit && length > 0 {
+		return x / length * limit, y / length * limit
+	}
+	return x, y
+}
+
+// applyFlocking steers every boid using the entities within its perception radius,
+// found through a spatial hash built once per step
+func (pe *PhysicsEngine) applyFlocking(entities []Entity) {
+	var index *spatialHash
+	for _, entity := range entities {
+		boid, ok := entity.(*Boid)
+		if !ok {
+			continue
+		}
+		if index == nil {
+			index = newSpatialHash(entities)
+		}
+		neighbors := queryRadius(index, boid.X, boid.Y, boid.Params.PerceptionRadius)
+		boid.Steer(neighbors, pe.MinX, pe.MinY, pe.MaxX, pe.MaxY, pe.DeltaTime)
+	}
+}
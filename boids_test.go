@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNewBoid(t *testing.T) {
+	boid := NewBoid(10.0, 5.0, 2, lipgloss.Color("32"))
+
+	if boid.GetType() != BoidType {
+		t.Errorf("Expected type %s, got %s", BoidType, boid.GetType())
+	}
+
+	vx, vy := boid.GetVelocity()
+	speed := math.Sqrt(vx*vx + vy*vy)
+	if speed < boid.Params.MinSpeed || speed > boid.Params.MaxSpeed {
+		t.Errorf("Expected initial cruise speed within [%.1f, %.1f], got %.2f",
+			boid.Params.MinSpeed, boid.Params.MaxSpeed, speed)
+	}
+}
+
+func TestBoidIgnoresGravity(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	boid := NewBoid(50.0, 25.0, 1, lipgloss.Color("32"))
+	boid.SetVelocity(6.0, 0)
+
+	pe.ApplyPhysics([]Entity{boid})
+
+	_, vy := boid.GetVelocity()
+	if math.Abs(vy) > 0.01 {
+		t.Errorf("Expected boid to keep level flight, got vy=%.3f", vy)
+	}
+}
+
+func TestBoidSeparation(t *testing.T) {
+	b1 := NewBoid(20.0, 20.0, 1, lipgloss.Color("32"))
+	b2 := NewBoid(21.0, 20.0, 1, lipgloss.Color("32"))
+	b1.SetVelocity(0, 5)
+	b2.SetVelocity(0, 5)
+
+	b1.Steer([]Entity{b1, b2}, 0, 0, 100, 100, 0.1)
+
+	vx, _ := b1.GetVelocity()
+	if vx >= 0 {
+		t.Errorf("Expected boid to steer away from its neighbor (negative vx), got %.3f", vx)
+	}
+}
+
+func TestBoidAlignment(t *testing.T) {
+	boid := NewBoid(50.0, 50.0, 1, lipgloss.Color("32"))
+	boid.Params.SeparationWeight = 0
+	boid.Params.CohesionWeight = 0
+	boid.SetVelocity(0, 6)
+
+	mate := NewBoid(54.0, 50.0, 1, lipgloss.Color("32"))
+	mate.SetVelocity(6, 0)
+
+	boid.Steer([]Entity{boid, mate}, 0, 0, 100, 100, 0.1)
+
+	vx, _ := boid.GetVelocity()
+	if vx <= 0 {
+		t.Errorf("Expected boid to turn toward its flockmate's heading, got vx=%.3f", vx)
+	}
+}
+
+func TestBoidAvoidsObstaclesButIgnoresTheirHeading(t *testing.T) {
+	boid := NewBoid(50.0, 50.0, 1, lipgloss.Color("32"))
+	boid.SetVelocity(0, 6)
+
+	sphere := NewSphere(51.0, 50.0, 4, lipgloss.Color("31"))
+	sphere.SetVelocity(0, -40)
+
+	boid.Steer([]Entity{boid, sphere}, 0, 0, 100, 100, 0.1)
+
+	vx, vy := boid.GetVelocity()
+	if vx >= 0 {
+		t.Errorf("Expected boid to steer away from the sphere, got vx=%.3f", vx)
+	}
+	if vy <= 0 {
+		t.Errorf("Expected boid not to align with a sphere, got vy=%.3f", vy)
+	}
+}
+
+func TestBoidWallAvoidance(t *testing.T) {
+	boid := NewBoid(98.0, 50.0, 1, lipgloss.Color("32"))
+	boid.SetVelocity(6, 0)
+
+	for i := 0; i < 5; i++ {
+		boid.Steer([]Entity{boid}, 1, 1, 99, 99, 0.1)
+	}
+
+	vx, _ := boid.GetVelocity()
+	if vx >= 6 {
+		t.Errorf("Expected boid to slow its approach to the right wall, got vx=%.3f", vx)
+	}
+}
+
+func TestBoidHeadingGlyph(t *testing.T) {
+	boid := NewBoid(10.0, 10.0, 1, lipgloss.Color("32"))
+
+	headings := []struct {
+		vx, vy float64
+		glyph  string
+	}{
+		{5, 0, "→"},
+		{0, 5, "↓"},
+		{-5, 0, "←"},
+		{0, -5, "↑"},
+		{5, -5, "↗"},
+	}
+
+	for _, h := range headings {
+		boid.SetVelocity(h.vx, h.vy)
+		boid.Update(0.01)
+		if boid.GetSymbol() != h.glyph {
+			t.Errorf("Velocity (%.0f, %.0f): expected glyph %s, got %s", h.vx, h.vy, h.glyph, boid.GetSymbol())
+		}
+		if boid.Render().Glyph != h.glyph {
+			t.Errorf("Velocity (%.0f, %.0f): expected render to contain %s", h.vx, h.vy, h.glyph)
+		}
+	}
+}
+
+func TestBoidsShareEntityManager(t *testing.T) {
+	manager := NewEntityManager()
+	manager.AddEntity(NewSphere(5.0, 5.0, 1, lipgloss.Color("32")))
+	manager.AddEntity(NewBoid(10.0, 5.0, 1, lipgloss.Color("32")))
+	manager.AddEntity(NewBoid(12.0, 5.0, 1, lipgloss.Color("32")))
+
+	if manager.CountByType(BoidType) != 2 {
+		t.Errorf("Expected 2 boids, got %d", manager.CountByType(BoidType))
+	}
+	if manager.CountByType(SphereType) != 1 {
+		t.Errorf("Expected 1 sphere, got %d", manager.CountByType(SphereType))
+	}
+}
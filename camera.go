@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Camera maps world coordinates to the cells of the simulation pane.
+// At zoom 1 each character shows one world cell, or one dot of it in the
+// high-resolution render modes; at zoom n it shows an n×n block per dot.
+type Camera struct {
+	X, Y       int    // World cell shown in the top-left corner of the view
+	Zoom       int    // World cells per dot along each axis
+	FollowID   string // Entity kept in the middle of the view; empty for none
+	ResX, ResY int    // Dots per character, set by the render mode; 0 means 1
+}
+
+const (
+	maxZoom      = 8 // Furthest the camera zooms out
+	cameraPanKey = 4 // Characters the view moves per pan key press
+)
+
+// NewCamera returns a camera at the world origin showing every cell
+func NewCamera() Camera {
+	return Camera{Zoom: 1}
+}
+
+func (c Camera) zoom() int {
+	return max(1, c.Zoom)
+}
+
+// cellSize returns the world cells covered by one character along each axis
+func (c Camera) cellSize() (width, height int) {
+	return c.zoom() * max(1, c.ResX), c.zoom() * max(1, c.ResY)
+}
+
+// ToScreen returns the view cell showing a world position
+func (c Camera) ToScreen(x, y float64) (sx, sy int) {
+	width, height := c.cellSize()
+	return int(math.Floor((x - float64(c.X)) / float64(width))), int(math.Floor((y - float64(c.Y)) / float64(height)))
+}
+
+// ToDot returns the view dot showing a world position, counting ResX × ResY dots per cell
+func (c Camera) ToDot(x, y float64) (dx, dy int) {
+	zoom := float64(c.zoom())
+	return int(math.Floor((x - float64(c.X)) / zoom)), int(math.Floor((y - float64(c.Y)) / zoom))
+}
+
+// ToWorld returns the world position at the middle of a view cell
+func (c Camera) ToWorld(sx, sy int) (x, y float64) {
+	width, height := c.cellSize()
+	return float64(c.X+sx*width) + float64(width)/2, float64(c.Y+sy*height) + float64(height)/2
+}
+
+// BlockAt returns the block of world cells shown by the character that shows a
+// world position
+func (c Camera) BlockAt(x, y float64) (left, top, width, height int) {
+	sx, sy := c.ToScreen(x, y)
+	width, height = c.cellSize()
+	return c.X + sx*width, c.Y + sy*height, width, height
+}
+
+// Pan moves the view by whole characters and stops following
+func (c *Camera) Pan(dx, dy int) {
+	width, height := c.cellSize()
+	c.X += dx * width
+	c.Y += dy * height
+	c.FollowID = ""
+}
+
+// Reset returns to the world origin at zoom 1, keeping the render mode's resolution
+func (c *Camera) Reset() {
+	*c = Camera{Zoom: 1, ResX: c.ResX, ResY: c.ResY}
+}
+
+// moved reports whether the camera has left its reset position
+func (c Camera) moved() bool {
+	return c.X != 0 || c.Y != 0 || c.zoom() != 1 || c.FollowID != ""
+}
+
+// ZoomBy changes the zoom level, keeping the middle of a viewWidth × viewHeight view in place
+func (c *Camera) ZoomBy(delta, viewWidth, viewHeight int) {
+	centerX, centerY := c.ToWorld(viewWidth/2, viewHeight/2)
+	c.Zoom = max(1, min(maxZoom, c.zoom()+delta))
+	c.CenterOn(centerX, centerY, viewWidth, viewHeight)
+}
+
+// CenterOn moves the view so a world position is in its middle
+func (c *Camera) CenterOn(x, y float64, viewWidth, viewHeight int) {
+	width, height := c.cellSize()
+	c.X = int(math.Floor(x)) - viewWidth*width/2
+	c.Y = int(math.Floor(y)) - viewHeight*height/2
+}
+
+// Clamp keeps the view inside the world. A world narrower or shorter than the view
+// is shown from its left or top edge.
+func (c *Camera) Clamp(viewWidth, viewHeight, worldWidth, worldHeight int) {
+	width, height := c.cellSize()
+	c.X = max(0, min(c.X, worldWidth-viewWidth*width))
+	c.Y = max(0, min(c.Y, worldHeight-viewHeight*height))
+}
+
+// plot draws a cell at a world position if it is in view
+func (c Camera) plot(grid [][]Cell, x, y float64, cell Cell) {
+	sx, sy := c.ToScreen(x, y)
+	if sy >= 0 && sy < len(grid) && sx >= 0 && sx < len(grid[sy]) {
+		grid[sy][sx] = cell
+	}
+}
+
+// plotCell draws a cell at an integer world cell if it is in view
+func (c Camera) plotCell(grid [][]Cell, x, y int, cell Cell) {
+	c.plot(grid, float64(x)+0.5, float64(y)+0.5, cell)
+}
+
+// String describes the camera for the status line
+func (c Camera) String() string {
+	text := fmt.Sprintf("(%d,%d) %d×", c.X, c.Y, c.zoom())
+	if c.FollowID != "" {
+		text += " following " + c.FollowID
+	}
+	return text
+}
+
+// parseWorldSize parses a world size flag such as "240x80"
+func parseWorldSize(value string) (width, height int, err error) {
+	w, h, ok := strings.Cut(strings.ToLower(value), "x")
+	if ok {
+		width, err = strconv.Atoi(w)
+		if err == nil {
+			height, err = strconv.Atoi(h)
+		}
+	}
+	if !ok || err != nil || width < 1 || height < 1 {
+		return 0, 0, fmt.Errorf("world size %q must look like 240x80", value)
+	}
+	return width, height, nil
+}
+
+// worldSize returns the size of the physics world in cells. Unless a fixed size
+// was set it follows the simulation pane, scaled by the render mode's dots per character.
+func (m Model) worldSize() (width, height int) {
+	if m.worldWidth > 0 && m.worldHeight > 0 {
+		return m.worldWidth, m.worldHeight
+	}
+	resX, resY := m.renderMode.Resolution()
+	return m.simWidth * resX, max(1, m.simHeight-8) * resY // Must match renderSimulation grid calculation
+}
+
+// SetWorldSize fixes the world size, independent of the terminal size
+func (m *Model) SetWorldSize(width, height int) {
+	m.worldWidth, m.worldHeight = width, height
+	m.physicsEngine.UpdateBounds(float64(width), float64(height))
+	m.materials.Resize(width, height)
+	m.clampCamera()
+}
+
+// materialGridSize returns the size of the material layer: the world when its size is
+// fixed, otherwise the rendered grid at the render mode's resolution
+func (m Model) materialGridSize() (width, height int) {
+	if m.worldWidth > 0 && m.worldHeight > 0 {
+		return m.worldWidth, m.worldHeight
+	}
+	width, height = m.simGridSize()
+	resX, resY := m.renderMode.Resolution()
+	return width * resX, height * resY
+}
+
+// clampCamera keeps the camera inside the world
+func (m *Model) clampCamera() {
+	viewWidth, viewHeight := m.simGridSize()
+	worldWidth, worldHeight := m.worldSize()
+	m.camera.Clamp(viewWidth, viewHeight, worldWidth, worldHeight)
+}
+
+// followCamera keeps the followed entity in the middle of the view
+func (m *Model) followCamera() {
+	if m.camera.FollowID == "" {
+		return
+	}
+	entity, ok := m.entityManager.Get(m.camera.FollowID)
+	if !ok {
+		m.camera.FollowID = "" // It was removed
+		return
+	}
+	viewWidth, viewHeight := m.simGridSize()
+	x, y := entity.GetDisplayPosition()
+	m.camera.CenterOn(x, y, viewWidth, viewHeight)
+	m.clampCamera()
+}
+
+// toggleFollow follows the inspected entity, or the first one, or stops following
+func (m *Model) toggleFollow() {
+	if m.camera.FollowID != "" {
+		m.camera.FollowID = ""
+		return
+	}
+	if entity, ok := m.selectedEntity(); ok {
+		m.camera.FollowID = entity.GetID()
+	} else if entities := m.entityManager.GetEntities(); len(entities) > 0 {
+		m.camera.FollowID = entities[0].GetID()
+	}
+	m.followCamera()
+}
+
+// handleCameraKey pans, zooms and follows, and reports whether it used the key
+func (m *Model) handleCameraKey(key string) bool {
+	viewWidth, viewHeight := m.simGridSize()
+	switch key {
+	case "ctrl+left":
+		m.camera.Pan(-cameraPanKey, 0)
+	case "ctrl+right":
+		m.camera.Pan(cameraPanKey, 0)
+	case "ctrl+up":
+		m.camera.Pan(0, -cameraPanKey)
+	case "ctrl+down":
+		m.camera.Pan(0, cameraPanKey)
+	case "+", "=":
+		m.camera.ZoomBy(-1, viewWidth, viewHeight) // Fewer cells per character
+	case "-":
+		m.camera.ZoomBy(1, viewWidth, viewHeight)
+	case "@":
+		m.toggleFollow()
+	case "0":
+		m.camera.Reset()
+	default:
+		return false
+	}
+	m.followCamera()
+	m.clampCamera()
+	return true
+}
+
+// sampleBlock returns the first non-empty cell of the world block shown at a view cell
+func (c Camera) sampleBlock(sx, sy int, render func(x, y int) Cell) Cell {
+	width, height := c.cellSize()
+	for dy := 0; dy < height; dy++ {
+		for dx := 0; dx < width; dx++ {
+			if cell := render(c.X+sx*width+dx, c.Y+sy*height+dy); !cell.Empty() {
+				return cell
+			}
+		}
+	}
+	return Cell{}
+}
+
+// handleCameraMouse zooms with the wheel and pans with a right-button drag in the
+// simulation pane, and reports whether it used the event
+func (m *Model) handleCameraMouse(msg tea.MouseMsg) bool {
+	viewWidth, viewHeight := m.simGridSize()
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && m.cursorInSim:
+		m.camera.ZoomBy(-1, viewWidth, viewHeight)
+	case msg.Button == tea.MouseButtonWheelDown && m.cursorInSim:
+		m.camera.ZoomBy(1, viewWidth, viewHeight)
+	case msg.Button == tea.MouseButtonRight && msg.Action == tea.MouseActionPress && m.cursorInSim:
+		m.panning = true
+		m.panFromX, m.panFromY = msg.X, msg.Y
+		return true
+	case m.panning && msg.Action == tea.MouseActionMotion:
+		// Dragging moves the world with the pointer
+		m.camera.Pan(m.panFromX-msg.X, m.panFromY-msg.Y)
+		m.panFromX, m.panFromY = msg.X, msg.Y
+	case m.panning && msg.Action == tea.MouseActionRelease:
+		m.panning = false
+		return true
+	default:
+		return false
+	}
+	m.followCamera()
+	m.clampCamera()
+	return true
+}
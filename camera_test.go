@@ -0,0 +1,172 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestCameraMapping(t *testing.T) {
+	cam := Camera{X: 10, Y: 4, Zoom: 2}
+	if sx, sy := cam.ToScreen(15.5, 9); sx != 2 || sy != 2 {
+		t.Errorf("Expected (15.5, 9) on screen cell (2, 2), got (%d, %d)", sx, sy)
+	}
+	x, y := cam.ToWorld(2, 2)
+	if x != 15 || y != 9 {
+		t.Errorf("Expected screen cell (2, 2) to show the block centered on (15, 9), got (%.1f, %.1f)", x, y)
+	}
+	if sx, sy := cam.ToScreen(x, y); sx != 2 || sy != 2 {
+		t.Errorf("Expected a round trip to land on the same cell, got (%d, %d)", sx, sy)
+	}
+
+	// Zooming keeps the middle of the view where it was
+	cam = Camera{X: 100, Y: 50, Zoom: 1}
+	beforeX, beforeY := cam.ToWorld(20, 10)
+	cam.ZoomBy(1, 40, 20)
+	afterX, afterY := cam.ToWorld(20, 10)
+	if cam.Zoom != 2 || afterX-beforeX > 1 || beforeX-afterX > 1 || afterY-beforeY > 1 || beforeY-afterY > 1 {
+		t.Errorf("Expected zoom 2 around (%.1f, %.1f), got zoom %d around (%.1f, %.1f)", beforeX, beforeY, cam.Zoom, afterX, afterY)
+	}
+
+	cam.Clamp(40, 20, 60, 30)
+	if cam.X != 0 || cam.Y != 0 {
+		t.Errorf("Expected a world smaller than the view to be shown from its corner, got (%d, %d)", cam.X, cam.Y)
+	}
+	cam = Camera{X: 500, Y: -5, Zoom: 1}
+	cam.Clamp(40, 20, 200, 100)
+	if cam.X != 160 || cam.Y != 0 {
+		t.Errorf("Expected the view clamped inside the world, got (%d, %d)", cam.X, cam.Y)
+	}
+
+	if _, _, err := parseWorldSize("240x80"); err != nil {
+		t.Errorf("Expected 240x80 to parse: %v", err)
+	}
+	if _, _, err := parseWorldSize("wide"); err == nil {
+		t.Error("Expected a malformed world size to be rejected")
+	}
+}
+
+func TestCameraExploresLargeWorld(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	model.SetWorldSize(400, 200)
+	if model.physicsEngine.MaxX != 398 || model.materials.Width != 400 {
+		t.Fatal("Expected a fixed world size to set the physics bounds and material grid")
+	}
+
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	spheresDrawn := func() int {
+		model.renderSimulation()
+		return buffer.Count("●")
+	}
+	far := NewSphere(250.5, 60.5, 1, lipgloss.Color("#FF00FF"))
+	far.SetFrozen(true)
+	model.entityManager.AddEntity(far)
+	if spheresDrawn() != 0 {
+		t.Fatal("Expected an entity outside the view not to be drawn")
+	}
+
+	press := func(msg tea.KeyMsg) {
+		updatedModel, _ := model.Update(msg)
+		model = updatedModel.(Model)
+	}
+	for i := 0; i < 3; i++ {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	}
+	if model.camera.Zoom != 4 {
+		t.Fatalf("Expected three zoom-outs to reach 4×, got %d×", model.camera.Zoom)
+	}
+	if spheresDrawn() != 1 {
+		t.Error("Expected the far entity to come into view when zoomed out")
+	}
+	if !strings.Contains(model.renderSimulation(), "Camera") {
+		t.Error("Expected the status line to describe the camera")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}})
+	for i := 0; i < 40; i++ {
+		press(tea.KeyMsg{Type: tea.KeyCtrlRight})
+		press(tea.KeyMsg{Type: tea.KeyCtrlDown})
+	}
+	if model.camera.X == 0 || model.camera.Y == 0 {
+		t.Fatal("Expected ctrl+arrows to pan the camera")
+	}
+	viewWidth, viewHeight := model.simGridSize()
+	if model.camera.X+viewWidth > 400 || model.camera.Y+viewHeight > 200 {
+		t.Errorf("Expected panning to stop at the world edge, got (%d, %d)", model.camera.X, model.camera.Y)
+	}
+
+	// Clicks land in the world under the pointer, not the top-left of the world
+	x, y, ok := model.screenToSim(5, 5)
+	if !ok || x < float64(model.camera.X) || y < float64(model.camera.Y) {
+		t.Errorf("Expected a click to map through the camera, got (%.1f, %.1f)", x, y)
+	}
+	if _, _, ok := model.screenToSim(SimGridOriginX+viewWidth-1, SimGridOriginY); !ok {
+		t.Error("Expected a click in the last drawn column to land in the simulation")
+	}
+	if _, _, ok := model.screenToSim(SimGridOriginX+viewWidth, SimGridOriginY); ok {
+		t.Error("Expected a click right of the drawn grid to miss the simulation")
+	}
+}
+
+func TestCameraFollowAndMouse(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	model.SetWorldSize(400, 200)
+
+	target := NewSphere(200.5, 100.5, 1, lipgloss.Color("#FF00FF"))
+	target.SetFrozen(true)
+	model.entityManager.AddEntity(target)
+	model.inspector.SelectedID = target.GetID()
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	model = updatedModel.(Model)
+	viewWidth, viewHeight := model.simGridSize()
+	if sx, sy := model.camera.ToScreen(target.GetDisplayPosition()); sx != viewWidth/2 || sy != viewHeight/2 {
+		t.Errorf("Expected the followed entity in the middle of the view, got (%d, %d)", sx, sy)
+	}
+	model.entityManager.RemoveEntity(target.GetID())
+	model.followCamera()
+	if model.camera.FollowID != "" {
+		t.Error("Expected following to stop when the entity is removed")
+	}
+
+	// Find a screen cell inside the simulation pane to drive the mouse from
+	var screenX, screenY int
+	for screenY = 0; screenY < 40; screenY++ {
+		if _, _, ok := model.screenToSim(30, screenY); ok {
+			screenX = 30
+			break
+		}
+	}
+	mouse := func(button tea.MouseButton, action tea.MouseAction, x, y int) {
+		updatedModel, _ := model.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: action})
+		model = updatedModel.(Model)
+	}
+
+	mouse(tea.MouseButtonWheelDown, tea.MouseActionPress, screenX, screenY)
+	if model.camera.Zoom != 2 {
+		t.Fatalf("Expected the wheel to zoom out, got %d×", model.camera.Zoom)
+	}
+	mouse(tea.MouseButtonWheelUp, tea.MouseActionPress, screenX, screenY)
+	if model.camera.Zoom != 1 {
+		t.Fatalf("Expected the wheel to zoom back in, got %d×", model.camera.Zoom)
+	}
+
+	startX, startY := model.camera.X, model.camera.Y
+	mouse(tea.MouseButtonRight, tea.MouseActionPress, screenX, screenY+2)
+	mouse(tea.MouseButtonRight, tea.MouseActionMotion, screenX-5, screenY)
+	mouse(tea.MouseButtonRight, tea.MouseActionRelease, screenX-5, screenY)
+	if model.camera.X != startX+5 || model.camera.Y != startY+2 {
+		t.Errorf("Expected dragging to pull the world along, got camera (%d, %d) from (%d, %d)",
+			model.camera.X, model.camera.Y, startX, startY)
+	}
+	if model.panning || model.entityManager.Count() != 0 {
+		t.Error("Expected a right-button drag to pan without spawning")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+// CollisionLayer is a bitmask of collision categories.
+// An entity belongs to one or more categories and collides only with things
+// whose category is in its mask, and only if its own category is in theirs.
+type CollisionLayer uint32
+
+const (
+	LayerNone     CollisionLayer = 0
+	LayerDefault  CollisionLayer = 1 << 0 // Ordinary entities
+	LayerGhost    CollisionLayer = 1 << 1 // Entities that pass through other entities
+	LayerWall     CollisionLayer = 1 << 2 // Simulation bounds
+	LayerObstacle CollisionLayer = 1 << 3 // Solid cells of the material layer
+	LayerAll      CollisionLayer = ^CollisionLayer(0)
+)
+
+// Ghost entities still bounce off walls and obstacles but ignore other entities
+const ghostCollisionMask = LayerWall | LayerObstacle
+
+// collisionFilter is implemented by entities that carry collision layers
+type collisionFilter interface {
+	GetCollisionLayers() (category, mask CollisionLayer)
+}
+
+// collisionLayersOf returns an entity's category and mask.
+// Entities without layers belong to LayerDefault and collide with everything.
+func collisionLayersOf(entity Entity) (category, mask CollisionLayer) {
+	if filter, ok := entity.(collisionFilter); ok {
+		return filter.GetCollisionLayers()
+	}
+	return LayerDefault, LayerAll
+}
+
+// layersInteract reports whether two category/mask pairs accept each other
+func layersInteract(category1, mask1, category2, mask2 CollisionLayer) bool {
+	return category1&mask2 != 0 && category2&mask1 != 0
+}
+
+// shouldCollide is the broadphase layer check for a pair of entities
+func shouldCollide(e1, e2 Entity) bool {
+	category1, mask1 := collisionLayersOf(e1)
+	category2, mask2 := collisionLayersOf(e2)
+	return layersInteract(category1, mask1, category2, mask2)
+}
+
+// collidesWithWalls reports whether an entity is kept in by the simulation bounds
+func (pe *PhysicsEngine) collidesWithWalls(entity Entity) bool {
+	category, mask := collisionLayersOf(entity)
+	return layersInteract(category, mask, pe.WallCategory, pe.WallMask)
+}
+
+// collidesWithObstacles reports whether an entity bounces off solid material cells
+func (pe *PhysicsEngine) collidesWithObstacles(entity Entity) bool {
+	category, mask := collisionLayersOf(entity)
+	return layersInteract(category, mask, pe.ObstacleCategory, pe.ObstacleMask)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDefaultEntitiesCollide(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	sphere := NewSphere(10.0, 10.0, 2, lipgloss.Color("32"))
+	sprite := NewSprite(10.5, 10.0, 2, lipgloss.Color("31"), "★")
+
+	if len(pe.findCollisions([]Entity{sphere, sprite})) != 1 {
+		t.Error("Expected default-layer entities to collide")
+	}
+}
+
+func TestGhostPassesThroughEntities(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	sphere := NewSphere(10.0, 10.0, 2, lipgloss.Color("32"))
+	ghost := NewSprite(10.5, 10.0, 2, lipgloss.Color("31"), "★")
+	ghost.MakeGhost()
+
+	if len(pe.findCollisions([]Entity{sphere, ghost})) != 0 {
+		t.Error("Expected ghost sprite to pass through the sphere")
+	}
+	if sphere.CheckCollision(ghost) || ghost.CheckCollision(sphere) {
+		t.Error("Expected CheckCollision to respect collision layers in both directions")
+	}
+
+	// Two ghosts ignore each other as well
+	other := NewSprite(10.2, 10.0, 2, lipgloss.Color("31"), "★")
+	other.MakeGhost()
+	if len(pe.findCollisions([]Entity{ghost, other})) != 0 {
+		t.Error("Expected ghosts to pass through each other")
+	}
+}
+
+func TestGhostStillBouncesOffWalls(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	ghost := NewSprite(97.0, 10.0, 1, lipgloss.Color("31"), "★")
+	ghost.MakeGhost()
+	ghost.SetVelocity(20.0, 0)
+
+	pe.ApplyPhysics([]Entity{ghost})
+
+	if vx, _ := ghost.GetVelocity(); vx >= 0 {
+		t.Errorf("Expected ghost to bounce off the right wall, got vx=%.2f", vx)
+	}
+}
+
+func TestLiteralEntityCollidesByDefault(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	crate := &BaseEntity{ID: "crate", X: 97.0, Y: 10.0, VX: 20.0, Mass: 1, Size: 1, Symbol: "▣"}
+
+	pe.ApplyPhysics([]Entity{crate})
+	if vx, _ := crate.GetVelocity(); vx >= 0 {
+		t.Errorf("Expected an entity built without layers to bounce off the right wall, got vx=%.2f", vx)
+	}
+	sphere := NewSphere(crate.X-0.5, crate.Y, 2, lipgloss.Color("32"))
+	if len(pe.findCollisions([]Entity{sphere, crate})) != 1 {
+		t.Error("Expected an entity built without layers to collide with other entities")
+	}
+}
+
+func TestOneWayMaskIsNotEnough(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	sphere := NewSphere(10.0, 10.0, 2, lipgloss.Color("32"))
+	sprite := NewSprite(10.5, 10.0, 2, lipgloss.Color("31"), "★")
+
+	// Sprite wants to hit the sphere, but the sphere does not accept sprites' category
+	sprite.SetCollisionLayers(LayerGhost, LayerAll)
+	sphere.SetCollisionLayers(LayerDefault, LayerDefault|LayerWall)
+
+	if len(pe.findCollisions([]Entity{sphere, sprite})) != 0 {
+		t.Error("Expected collision to require both masks to accept the other category")
+	}
+}
+
+func TestWallMaskLetsEntitiesLeave(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	pe.WallMask = LayerAll &^ LayerGhost
+
+	ghost := NewSprite(97.0, 10.0, 1, lipgloss.Color("31"), "★")
+	ghost.MakeGhost()
+	ghost.SetVelocity(20.0, 0)
+
+	pe.ApplyPhysics([]Entity{ghost})
+
+	if vx, _ := ghost.GetVelocity(); vx <= 0 {
+		t.Errorf("Expected ghost to pass through walls that exclude its layer, got vx=%.2f", vx)
+	}
+}
+
+func TestObstacleMask(t *testing.T) {
+	pe := NewPhysicsEngine(20, 20)
+	pe.Gravity = 0
+	pe.Materials = NewMaterialGrid(20, 20)
+	pe.Materials.Set(5, 10, MaterialStone)
+
+	sphere := NewSphere(5.5, 9.8, 1, lipgloss.Color("32"))
+	sphere.SetCollisionLayers(LayerDefault, LayerAll&^LayerObstacle)
+	sphere.SetVelocity(0, 5)
+
+	pe.ApplyPhysics([]Entity{sphere})
+
+	if _, y := sphere.GetPosition(); int(y) != 10 {
+		t.Errorf("Expected sphere masked from obstacles to enter the stone cell, got y=%.2f", y)
+	}
+}
+
+func TestModelGhostSpawning(t *testing.T) {
+	model := initialModel()
+	model.termWidth = 80
+	model.termHeight = 24
+	model.updatePaneDimensions()
+	model.ready = true
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model = updatedModel.(Model)
+
+	sprite := model.entityManager.GetEntities()[0].(*Sprite)
+	if !sprite.IsGhost() {
+		t.Error("Expected sprite spawned in ghost mode to be on the ghost layer")
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layer is one level of the simulation pane, drawn bottom to top
+type Layer int
+
+const (
+	LayerBackground Layer = iota // Blank cells
+	LayerFields                  // Trigger zones and other regions that affect entities
+	LayerTrails                  // Motion trails
+	LayerObstacles               // Materials
+	LayerEntities                // Entities, resolved by z-order and the overlap policy
+	LayerEffects                 // Sparks
+	LayerOverlay                 // Brush cursor, slingshot aim and other debug/UI marks
+	layerCount
+)
+
+// Cell is one character cell of the pane: a glyph and how it should look, with no
+// escape codes. Renderers decide how to show it; the zero Cell is transparent.
+type Cell struct {
+	Glyph      string
+	Color      lipgloss.Color // Foreground; "" for the terminal's default
+	Background lipgloss.Color // "" for the terminal's default
+	Bold       bool
+	Faint      bool
+	Reverse    bool
+}
+
+// Empty reports whether the cell is transparent
+func (c Cell) Empty() bool {
+	return c.Glyph == ""
+}
+
+// Compositor stacks a grid of cells per layer and flattens them into the
+// rendered grid. Each cell shows the topmost layer that drew into it.
+type Compositor struct {
+	Width, Height int
+	layers        [layerCount][][]Cell
+}
+
+// NewCompositor creates a compositor with every layer transparent and a blank background
+func NewCompositor(width, height int) *Compositor {
+	c := &Compositor{Width: width, Height: height}
+	for layer := range c.layers {
+		grid := make([][]Cell, height)
+		for y := range grid {
+			grid[y] = make([]Cell, width)
+		}
+		c.layers[layer] = grid
+	}
+	for _, row := range c.layers[LayerBackground] {
+		for x := range row {
+			row[x] = Cell{Glyph: " "}
+		}
+	}
+	return c
+}
+
+// Layer returns a layer's grid for drawing; cells left empty are transparent
+func (c *Compositor) Layer(layer Layer) [][]Cell {
+	return c.layers[layer]
+}
+
+// Occupied reports whether any layer above the background drew into a cell
+func (c *Compositor) Occupied(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+		return false
+	}
+	for layer := LayerBackground + 1; layer < layerCount; layer++ {
+		if !c.layers[layer][y][x].Empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Flatten returns the composed grid
+func (c *Compositor) Flatten() [][]Cell {
+	grid := make([][]Cell, c.Height)
+	for y := range grid {
+		grid[y] = make([]Cell, c.Width)
+		for x := range grid[y] {
+			for layer := layerCount - 1; layer >= LayerBackground; layer-- {
+				if cell := c.layers[layer][y][x]; !cell.Empty() {
+					grid[y][x] = cell
+					break
+				}
+			}
+		}
+	}
+	return grid
+}
+
+// OverlapPolicy decides what a cell shows when several entities are drawn into it
+type OverlapPolicy int
+
+const (
+	OverlapTopmost  OverlapPolicy = iota // The entity with the highest z-order
+	OverlapHeaviest                      // The entity with the most mass
+	OverlapCount                         // A badge with the number of entities
+)
+
+// overlapPolicyNames are shown in the status line
+var overlapPolicyNames = []string{"topmost", "heaviest", "count"}
+
+// String returns the policy's name
+func (policy OverlapPolicy) String() string {
+	return overlapPolicyNames[policy]
+}
+
+// zOrdered is implemented by entities with an explicit drawing order
+type zOrdered interface {
+	GetZOrder() int
+}
+
+// zOrderOf returns an entity's z-order; entities without one are at 0
+func zOrderOf(entity Entity) int {
+	if ordered, ok := entity.(zOrdered); ok {
+		return ordered.GetZOrder()
+	}
+	return 0
+}
+
+// spawnOrderOf returns when an entity was added to its manager; 0 if it never was
+func spawnOrderOf(entity Entity) int {
+	if spawned, ok := entity.(interface{ GetSpawnOrder() int }); ok {
+		return spawned.GetSpawnOrder()
+	}
+	return 0
+}
+
+// massOf returns an entity's mass; entities without one weigh 1
+func massOf(entity Entity) float64 {
+	if massive, ok := entity.(interface{ GetMass() float64 }); ok {
+		return massive.GetMass()
+	}
+	return 1
+}
+
+// sortByZOrder returns the entities ordered bottom to top. Entities with the same
+// z-order are ordered by spawn order, so the newest is on top; the manager's slice
+// order is no guide, since removals move the last entity into the gap.
+func sortByZOrder(entities []Entity) []Entity {
+	sorted := append([]Entity(nil), entities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if zi, zj := zOrderOf(sorted[i]), zOrderOf(sorted[j]); zi != zj {
+			return zi < zj
+		}
+		return spawnOrderOf(sorted[i]) < spawnOrderOf(sorted[j])
+	})
+	return sorted
+}
+
+// cellClaim is an entity's glyph for one cell, before overlaps are resolved
+type cellClaim struct {
+	entity Entity
+	cell   Cell
+}
+
+// drawEntityGlyphs draws entities bottom to top by z-order into the entity layer.
+// Where several entities land in one cell the overlap policy picks what is shown.
+func drawEntityGlyphs(grid [][]Cell, cam Camera, entities []Entity, selectedID string, policy OverlapPolicy) {
+	claims := make(map[[2]int][]cellClaim)
+	var order [][2]int
+	claim := func(entity Entity, x, y float64, cell Cell) {
+		sx, sy := cam.ToScreen(x, y)
+		if sy < 0 || sy >= len(grid) || sx < 0 || sx >= len(grid[sy]) {
+			return
+		}
+		key := [2]int{sx, sy}
+		if len(claims[key]) == 0 {
+			order = append(order, key)
+		}
+		claims[key] = append(claims[key], cellClaim{entity, cell})
+	}
+
+	for _, entity := range sortByZOrder(entities) {
+		forEachGlyph(entity, cam.zoom() == 1, func(x, y float64, cell Cell, whole bool) {
+			claim(entity, x, y, applyImpactEffects(entity, cell, whole))
+		})
+	}
+
+	for _, key := range order {
+		cell := resolveOverlap(claims[key], policy)
+		for _, c := range claims[key] {
+			if selectedID != "" && c.entity.GetID() == selectedID {
+				cell = highlightCell(cell, c.entity.GetColor())
+				break
+			}
+		}
+		grid[key[1]][key[0]] = cell
+	}
+}
+
+// forEachGlyph calls draw with the world position and glyph of every cell an entity
+// draws, at its animated display position. Large entities draw their whole shape
+// when shapes is set and otherwise shrink to a single glyph; whole is set for a
+// glyph that stands for the whole entity.
+func forEachGlyph(entity Entity, shapes bool, draw func(x, y float64, cell Cell, whole bool)) {
+	x, y := entity.GetDisplayPosition()
+	if shaped, ok := entity.(multiCellRenderer); ok && isMultiCell(entity.GetSize()) && shapes {
+		cells := shaped.RenderCells()
+		left, top := drawnOrigin(x, y, len(cells[0]), len(cells))
+		for dy, row := range cells {
+			for dx, cell := range row {
+				if !cell.Empty() {
+					draw(float64(left+dx)+0.5, float64(top+dy)+0.5, cell, false)
+				}
+			}
+		}
+		return
+	}
+	draw(x, y, entity.Render(), true)
+}
+
+// shownEntity returns the entity whose glyph the overlap policy shows for a cell
+// drawn by entities in bottom-to-top order. The count badge takes the topmost
+// entity's color, so it counts as showing that entity.
+func shownEntity(stack []Entity, policy OverlapPolicy) Entity {
+	shown := stack[len(stack)-1]
+	if policy == OverlapHeaviest {
+		for _, entity := range stack {
+			if massOf(entity) > massOf(shown) {
+				shown = entity
+			}
+		}
+	}
+	return shown
+}
+
+// resolveOverlap picks the glyph for a cell claimed by entities in bottom-to-top order
+func resolveOverlap(claims []cellClaim, policy OverlapPolicy) Cell {
+	if len(claims) == 1 {
+		return claims[0].cell
+	}
+	if policy == OverlapCount {
+		badge := "+"
+		if len(claims) <= 9 {
+			badge = strconv.Itoa(len(claims))
+		}
+		top := claims[len(claims)-1]
+		return Cell{Glyph: badge, Color: top.entity.GetColor(), Bold: true}
+	}
+	stack := make([]Entity, len(claims))
+	for i, c := range claims {
+		stack[i] = c.entity
+	}
+	shown := shownEntity(stack, policy)
+	for i := len(claims) - 1; i >= 0; i-- {
+		if claims[i].entity == shown {
+			return claims[i].cell
+		}
+	}
+	return claims[len(claims)-1].cell
+}
+
+// pickGlyph returns the entity drawEntityGlyphs shows in view cell (sx, sy), for
+// mouse picking: the same glyphs, z-order and overlap policy decide it
+func pickGlyph(cam Camera, entities []Entity, sx, sy int, policy OverlapPolicy) (Entity, bool) {
+	var stack []Entity
+	for _, entity := range sortByZOrder(entities) {
+		drawn := false
+		forEachGlyph(entity, cam.zoom() == 1, func(x, y float64, cell Cell, whole bool) {
+			if cx, cy := cam.ToScreen(x, y); cx == sx && cy == sy {
+				drawn = true
+			}
+		})
+		if drawn {
+			stack = append(stack, entity)
+		}
+	}
+	if len(stack) == 0 {
+		return nil, false
+	}
+	return shownEntity(stack, policy), true
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestCompositorLayerOrder(t *testing.T) {
+	comp := NewCompositor(3, 1)
+	comp.Layer(LayerOverlay)[0][0] = Cell{Glyph: "+"}
+	comp.Layer(LayerEntities)[0][0] = Cell{Glyph: "●"}
+	comp.Layer(LayerEntities)[0][1] = Cell{Glyph: "●"}
+	comp.Layer(LayerTrails)[0][1] = Cell{Glyph: "•"}
+	comp.Layer(LayerFields)[0][2] = Cell{Glyph: "┆"}
+
+	grid := comp.Flatten()
+	if grid[0][0].Glyph != "+" || grid[0][1].Glyph != "●" || grid[0][2].Glyph != "┆" {
+		t.Errorf("Expected each cell to show its topmost layer, got %+v", grid[0])
+	}
+	if !comp.Occupied(2, 0) || NewCompositor(1, 1).Occupied(0, 0) {
+		t.Error("Expected only drawn cells to count as occupied")
+	}
+	if NewCompositor(1, 1).Flatten()[0][0] != (Cell{Glyph: " "}) {
+		t.Error("Expected an untouched cell to show the blank background")
+	}
+}
+
+func TestOverlapPolicies(t *testing.T) {
+	light := NewSphere(5.5, 2.5, 1, lipgloss.Color("#FF0000"))
+	heavy := NewSphere(5.5, 2.5, 1, lipgloss.Color("#0000FF"))
+	heavy.SetMass(10)
+	light.SetZOrder(1) // Drawn over heavy although added first
+	entities := []Entity{light, heavy}
+
+	if sorted := sortByZOrder(entities); sorted[1] != light {
+		t.Fatal("Expected the higher z-order to sort on top")
+	}
+
+	draw := func(policy OverlapPolicy) Cell {
+		grid := NewCompositor(10, 5).Layer(LayerEntities)
+		drawEntityGlyphs(grid, NewCamera(), entities, "", policy)
+		return grid[2][5]
+	}
+	if cell := draw(OverlapTopmost); cell != light.Render() {
+		t.Errorf("Expected the topmost entity, got %+v", cell)
+	}
+	if cell := draw(OverlapHeaviest); cell != heavy.Render() {
+		t.Errorf("Expected the heaviest entity, got %+v", cell)
+	}
+	if cell := draw(OverlapCount); cell.Glyph != "2" || cell.Color != light.GetColor() {
+		t.Errorf("Expected a count badge of 2 in the topmost entity's color, got %+v", cell)
+	}
+}
+
+func TestPickingMatchesDrawing(t *testing.T) {
+	manager := NewEntityManager()
+	first := NewSphere(5.5, 2.5, 1, lipgloss.Color("#FF0000"))
+	bottom := NewSprite(5.5, 2.5, 1, lipgloss.Color("#00FF00"), "★")
+	top := NewSphere(5.5, 2.5, 1, lipgloss.Color("#0000FF"))
+	for _, entity := range []Entity{first, bottom, top} {
+		manager.AddEntity(entity)
+	}
+	// Removing the first entity moves the newest into its slot
+	manager.RemoveEntity(first.GetID())
+	entities := manager.GetEntities()
+	if entities[0] != Entity(top) {
+		t.Fatal("Expected the removal to reorder the manager's slice")
+	}
+	if sorted := sortByZOrder(entities); sorted[1] != Entity(top) {
+		t.Error("Expected equal z-orders to stay in spawn order after a removal")
+	}
+
+	cam := NewCamera()
+	for _, policy := range []OverlapPolicy{OverlapTopmost, OverlapHeaviest, OverlapCount} {
+		if policy == OverlapHeaviest {
+			bottom.SetMass(10)
+		}
+		grid := NewCompositor(10, 5).Layer(LayerEntities)
+		drawEntityGlyphs(grid, cam, entities, "", policy)
+		picked, ok := pickGlyph(cam, entities, 5, 2, policy)
+		if !ok {
+			t.Fatalf("%s: expected a pick where entities are drawn", policy)
+		}
+		if policy != OverlapCount && grid[2][5] != picked.Render() {
+			t.Errorf("%s: expected the pick to be the entity drawn, got %+v for %+v", policy, picked.Render(), grid[2][5])
+		}
+	}
+	if picked, _ := pickGlyph(cam, entities, 5, 2, OverlapHeaviest); picked != Entity(bottom) {
+		t.Error("Expected the heaviest policy to pick the heaviest entity")
+	}
+	if _, ok := pickGlyph(cam, entities, 6, 2, OverlapTopmost); ok {
+		t.Error("Expected an empty cell to have no entity")
+	}
+
+	// Zoomed out, the character shows its whole block; dots pick the topmost by z-order
+	cam.Zoom = 4
+	if picked, ok := pickGlyph(cam, entities, 1, 0, OverlapTopmost); !ok || picked != Entity(top) {
+		t.Error("Expected a zoomed-out pick to find the entity anywhere in the block")
+	}
+	bottom.SetZOrder(1)
+	if picked, ok := pickDot(NewCamera(), RenderBraille, entities, 2, 0); !ok || picked != Entity(bottom) {
+		t.Error("Expected a dot pick to find the entity drawn on top")
+	}
+}
+
+func TestModelOverlapPolicyAndZField(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	for i := 0; i < 3; i++ {
+		sphere := NewSphere(8.5, 4.5, 1, lipgloss.Color("#00FF00"))
+		sphere.SetFrozen(true)
+		model.entityManager.AddEntity(sphere)
+	}
+
+	press := func(key rune) {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		model = updatedModel.(Model)
+	}
+	press('O')
+	press('O')
+	if model.overlapPolicy != OverlapCount {
+		t.Fatalf("Expected O twice to reach the count badge, got %s", model.overlapPolicy)
+	}
+	comp := NewCompositor(model.simGridSize())
+	drawEntityGlyphs(comp.Layer(LayerEntities), model.camera, model.entityManager.GetEntities(), "", model.overlapPolicy)
+	if cell := comp.Layer(LayerEntities)[4][8]; cell.Glyph != "3" {
+		t.Errorf("Expected three stacked entities to show 3, got %q", cell.Glyph)
+	}
+	model.renderer = NewCellBuffer()
+	if !strings.Contains(model.renderSimulation(), "Overlap: count") {
+		t.Error("Expected the status line to show the overlap policy")
+	}
+	model.setRenderMode(RenderBraille)
+	if strings.Contains(model.renderSimulation(), "Overlap") {
+		t.Error("Expected dot modes, where the policy does not apply, to hide it")
+	}
+	model.setRenderMode(RenderGlyphs)
+
+	entity := model.entityManager.GetEntities()[0]
+	if err := inspectorFields[inspectorFieldIndex("Z")].Set(entity, "5"); err != nil || zOrderOf(entity) != 5 {
+		t.Errorf("Expected the inspector to set the z-order, got %d, %v", zOrderOf(entity), err)
+	}
+	if err := inspectorFields[inspectorFieldIndex("Z")].Set(entity, "up"); err == nil {
+		t.Error("Expected a non-numeric z-order to be rejected")
+	}
+}
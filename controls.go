@@ -21,6 +21,7 @@ const (
 	BounceAction      ButtonAction = "bounce"
 	SizeAction        ButtonAction = "size"
 	ColorAction       ButtonAction = "color"
+	SpringsAction     ButtonAction = "springs"
 )
 
 // Button represents an interactive button
@@ -93,14 +94,18 @@ func NewControlPanel(width, height int) *ControlPanel {
 			Bold(true),
 	}
 
-	// Create buttons - core 5 buttons as per original design
-	buttons := []Button{
-		{Label: "Add Sphere", Action: AddSphereAction, Width: 12},
-		{Label: "Add Sprite", Action: AddSpriteAction, Width: 12},
-		{Label: "Clear All", Action: ClearAllAction, Width: 11},
-		{Label: "Pause", Action: PauseResumeAction, Width: 7},
-		{Label: "Reset", Action: ResetAction, Width: 7},
+	// Create buttons - one add button per registered entity type, then the core controls
+	var buttons []Button
+	for _, info := range RegisteredEntityTypes() {
+		label := "Add " + info.Name
+		buttons = append(buttons, Button{Label: label, Action: addActionFor(info.Type), Width: len(label) + 2})
 	}
+	buttons = append(buttons,
+		Button{Label: "Clear All", Action: ClearAllAction, Width: 11},
+		Button{Label: "Pause", Action: PauseResumeAction, Width: 7},
+		Button{Label: "Reset", Action: ResetAction, Width: 7},
+		Button{Label: springsLabel(springPresets[defaultSpringPreset].Name), Action: SpringsAction, Width: 16},
+	)
 
 	return &ControlPanel{
 		buttons:      buttons,
@@ -124,7 +129,7 @@ func (cp *ControlPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "tab", "right":
 			if cp.ultraCompactMode {
 				// In ultra compact mode, only navigate between essential buttons
-				essentialButtons := []int{0, 1, 2, 3} // Add Sphere, Add Sprite, Clear, Pause
+				essentialButtons := cp.essentialButtons()
 				currentPos := -1
 				for i, idx := range essentialButtons {
 					if idx == cp.focused {
@@ -143,7 +148,7 @@ func (cp *ControlPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "shift+tab", "left":
 			if cp.ultraCompactMode {
 				// In ultra compact mode, only navigate between essential buttons
-				essentialButtons := []int{0, 1, 2, 3} // Add Sphere, Add Sprite, Clear, Pause
+				essentialButtons := cp.essentialButtons()
 				currentPos := -1
 				for i, idx := range essentialButtons {
 					if idx == cp.focused {
@@ -210,24 +215,9 @@ func (cp *ControlPanel) View() string {
 
 		// Line 1: Essential buttons only
 		var buttonParts []string
-		essentialButtons := []int{0, 1, 2, 3} // Add Sphere, Add Sprite, Clear, Pause
 
-		for _, idx := range essentialButtons {
-			var buttonText string
-			switch cp.buttons[idx].Action {
-			case AddSphereAction:
-				buttonText = "●"
-			case AddSpriteAction:
-				buttonText = "◆"
-			case ClearAllAction:
-				buttonText = "Clear"
-			case PauseResumeAction:
-				if cp.buttons[idx].Label == "Resume" {
-					buttonText = "▶"
-				} else {
-					buttonText = "⏸"
-				}
-			}
+		for _, idx := range cp.essentialButtons() {
+			buttonText := cp.getCompactLabel(cp.buttons[idx])
 
 			if idx == cp.focused {
 				buttonText = "→" + buttonText + "←"
@@ -249,7 +239,7 @@ func (cp *ControlPanel) View() string {
 		lines = append(lines, controlsLine)
 
 		// Line 2: Essential keys only
-		keyHints := "Keys: A●  S◆  C=Clear  P=Pause  F=Perf  TAB=Navigate"
+		keyHints := "Keys: " + entityKeyHints(false) + "  C=Clear  P=Pause  F=Perf  TAB=Navigate"
 		keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
 		lines = append(lines, keyStyle.Render(keyHints))
 
@@ -290,7 +280,7 @@ func (cp *ControlPanel) View() string {
 
 		// Line 3: Parameters and key hints combined
 		paramStatus := fmt.Sprintf("⚙️%s 📏%s 🎨%s", cp.gravityText, cp.sizeText, cp.colorText)
-		keyHints := " | Keys: A●  S◆  C=Clear  P=Pause  G=Gravity  B=Bounce  Z=Size  X=Color  F=Perf"
+		keyHints := " | Keys: " + entityKeyHints(false) + "  C=Clear  P=Pause  G=Gravity  B=Bounce  Z=Size  X=Color  F=Perf"
 		combinedLine := paramStatus + keyHints
 
 		paramStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#F39C12"))
@@ -332,7 +322,7 @@ func (cp *ControlPanel) View() string {
 		lines = append(lines, paramStyle.Render(paramStatus))
 
 		// Line 4: Key hints
-		keyHints := "Keys: A=Add●  S=Add◆  C=Clear  P=Pause  R=Reset  G=Gravity  B=Bounce  Z=Size  X=Color  F=Perf  T=Test  L=Limit  TAB=Navigate"
+		keyHints := "Keys: " + entityKeyHints(true) + "  E=Type  C=Clear  P=Pause  R=Reset  G=Gravity  W=Tilt  Y=Trails  I=Inspect  U=Prefab  D=Springs  B=Bounce  Z=Size  X=Color  V=Behavior  H=Ghost  K=Zone  J=ZoneKind  M=Material  N=Paint  F=Perf  ⇧F=Effects  ⇧R=Render  ⇧O=Overlap  Ctrl+Arrows=Pan  +/-=Zoom  @=Follow  0=Home  T=Test  L=Limit  TAB=Navigate"
 		keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
 		lines = append(lines, keyStyle.Render(keyHints))
 	}
@@ -340,13 +330,44 @@ func (cp *ControlPanel) View() string {
 	return strings.Join(lines, "\n")
 }
 
+// essentialButtons returns the indices of the buttons shown in ultra compact mode:
+// the add buttons, Clear and Pause
+func (cp *ControlPanel) essentialButtons() []int {
+	var indices []int
+	for i, button := range cp.buttons {
+		if _, isAdd := entityTypeForAction(button.Action); isAdd ||
+			button.Action == ClearAllAction || button.Action == PauseResumeAction {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// entityKeyHints lists the spawn keys of the registered entity types, e.g. "A●  S◆",
+// or "A=Add●  S=Add◆" when verbose
+func entityKeyHints(verbose bool) string {
+	var hints []string
+	for _, info := range RegisteredEntityTypes() {
+		if info.Key == "" {
+			continue
+		}
+		key := strings.ToUpper(info.Key)
+		if verbose {
+			hints = append(hints, key+"=Add"+info.Icon)
+		} else {
+			hints = append(hints, key+info.Icon)
+		}
+	}
+	return strings.Join(hints, "  ")
+}
+
 // getCompactLabel returns shortened button labels for compact modes
 func (cp *ControlPanel) getCompactLabel(button Button) string {
+	if entityType, ok := entityTypeForAction(button.Action); ok {
+		info, _ := LookupEntityType(entityType)
+		return info.Icon
+	}
 	switch button.Action {
-	case AddSphereAction:
-		return "●"
-	case AddSpriteAction:
-		return "◆"
 	case ClearAllAction:
 		return "Clear"
 	case PauseResumeAction:
@@ -364,6 +385,8 @@ func (cp *ControlPanel) getCompactLabel(button Button) string {
 		return "📏"
 	case ColorAction:
 		return "🎨"
+	case SpringsAction:
+		return "🌀"
 	default:
 		return button.Label
 	}
@@ -383,6 +406,21 @@ func (cp *ControlPanel) UpdatePauseButton(paused bool) {
 	}
 }
 
+// springsLabel returns the label of the springs button for a preset
+func springsLabel(preset string) string {
+	return "Springs: " + preset
+}
+
+// UpdateSpringsButton shows the selected spring preset on the springs button
+func (cp *ControlPanel) UpdateSpringsButton(preset string) {
+	for i := range cp.buttons {
+		if cp.buttons[i].Action == SpringsAction {
+			cp.buttons[i].Label = springsLabel(preset)
+			break
+		}
+	}
+}
+
 // SetButtonActive sets a button's active state
 func (cp *ControlPanel) SetButtonActive(action ButtonAction, active bool) {
 	for i := range cp.buttons {
@@ -22,8 +22,10 @@ func TestNewControlPanel(t *testing.T) {
 		t.Errorf("Expected height 20, got %d", cp.height)
 	}
 
-	if len(cp.buttons) != 5 {
-		t.Errorf("Expected 5 buttons, got %d", len(cp.buttons))
+	// One add button per registered entity type, plus Clear, Pause, Reset and Springs
+	expectedButtons := len(RegisteredEntityTypes()) + 4
+	if len(cp.buttons) != expectedButtons {
+		t.Errorf("Expected %d buttons, got %d", expectedButtons, len(cp.buttons))
 	}
 
 	// Check default focused button
@@ -34,6 +36,7 @@ func TestNewControlPanel(t *testing.T) {
 
 func TestControlPanelNavigation(t *testing.T) {
 	cp := NewControlPanel(80, 20)
+	buttonCount := len(cp.buttons)
 
 	// Test tab navigation
 	cp.Update(tea.KeyMsg{Type: tea.KeyTab})
@@ -45,15 +48,15 @@ func TestControlPanelNavigation(t *testing.T) {
 	for i := 0; i < 6; i++ {
 		cp.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
-	if cp.focused != 2 { // 7 total tabs: (0 + 7) % 5 = 2
-		t.Errorf("Expected focused button 2 after 7 total tabs, got %d", cp.focused)
+	if cp.focused != 7%buttonCount { // 7 total tabs wrap around the button row
+		t.Errorf("Expected focused button %d after 7 total tabs, got %d", 7%buttonCount, cp.focused)
 	}
 
 	// Test shift+tab (reverse navigation) from position 0
 	cp.focused = 0 // Reset to position 0
 	cp.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
-	if cp.focused != 4 { // Should wrap to last button (4)
-		t.Errorf("Expected focused button 4 after shift+tab from 0, got %d", cp.focused)
+	if cp.focused != buttonCount-1 { // Should wrap to last button
+		t.Errorf("Expected focused button %d after shift+tab from 0, got %d", buttonCount-1, cp.focused)
 	}
 }
 
@@ -0,0 +1,556 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Component types stored densely by ECSWorld. Row i of every component slice
+// belongs to the same entity, so systems walk flat slices instead of calling
+// interface getters and setters.
+
+// PositionComponent is an entity's physics position
+type PositionComponent struct{ X, Y float64 }
+
+// VelocityComponent is an entity's velocity
+type VelocityComponent struct{ VX, VY float64 }
+
+// ShapeComponent is an entity's size and the half extents of its collision bounds
+type ShapeComponent struct {
+	Size                  int
+	Kind                  ShapeKind
+	HalfWidth, HalfHeight float64
+}
+
+// RenderComponent is what an entity draws
+type RenderComponent struct {
+	Symbol string
+	Color  lipgloss.Color
+}
+
+// ECSWorld is an entity-component-system store for the simulation core.
+// Entities are rows in dense component slices and systems iterate over them;
+// ECSEntity handles expose rows through the Entity interface so renderers,
+// collisions and queries written against Entity keep working. Lifecycle hooks
+// fired during Step receive those handles and must not add or remove rows.
+type ECSWorld struct {
+	IDs        []string
+	Types      []EntityType
+	Positions  []PositionComponent
+	Velocities []VelocityComponent
+	Masses     []float64
+	Shapes     []ShapeComponent
+	Renders    []RenderComponent
+	Animations []*EntityAnimationState
+	Layers     [][2]CollisionLayer // Category and mask
+	Frozen     []bool
+	NoGravity  []bool // Self-propelled entities that ignore gravity
+
+	handles []*ECSEntity
+	asleep  []bool // Rows that came to rest on the last step
+	index   map[string]int
+	nextID  uint64
+}
+
+// NewECSWorld creates an empty world
+func NewECSWorld() *ECSWorld {
+	return &ECSWorld{index: make(map[string]int)}
+}
+
+// Len returns the number of entities in the world
+func (w *ECSWorld) Len() int {
+	return len(w.IDs)
+}
+
+// Spawn creates an entity of a registered type in the world
+func (w *ECSWorld) Spawn(entityType EntityType, x, y float64, size int, color lipgloss.Color) (*ECSEntity, bool) {
+	entity, ok := NewEntityOfType(entityType, x, y, size, color)
+	if !ok {
+		return nil, false
+	}
+	return w.Add(entity), true
+}
+
+// Add copies an entity's components into the world and returns its handle.
+// The original entity is not modified or tracked.
+func (w *ECSWorld) Add(entity Entity) *ECSEntity {
+	w.nextID++
+	id := fmt.Sprintf("%s_%d", entity.GetType(), w.nextID)
+
+	x, y := entity.GetPosition()
+	vx, vy := entity.GetVelocity()
+	_, _, width, height := entity.GetBounds()
+	category, mask := collisionLayersOf(entity)
+	mass := massFor(entity.GetType(), entity.GetSize()) // Entities without a mass get their type's
+	if massive, ok := entity.(interface{ GetMass() float64 }); ok {
+		mass = massive.GetMass()
+	}
+
+	w.IDs = append(w.IDs, id)
+	w.Types = append(w.Types, entity.GetType())
+	w.Positions = append(w.Positions, PositionComponent{x, y})
+	w.Velocities = append(w.Velocities, VelocityComponent{vx, vy})
+	w.Masses = append(w.Masses, mass)
+	w.Shapes = append(w.Shapes, ShapeComponent{
+		Size:       entity.GetSize(),
+		Kind:       shapeKindOf(entity),
+		HalfWidth:  width / 2,
+		HalfHeight: height / 2,
+	})
+	w.Renders = append(w.Renders, RenderComponent{entity.GetSymbol(), entity.GetColor()})
+	w.Animations = append(w.Animations, entity.GetAnimationState())
+	w.Layers = append(w.Layers, [2]CollisionLayer{category, mask})
+	w.Frozen = append(w.Frozen, isFrozen(entity))
+	exempt, ok := entity.(gravityExempt)
+	w.NoGravity = append(w.NoGravity, ok && exempt.IgnoresGravity())
+
+	handle := &ECSEntity{world: w, row: len(w.IDs) - 1}
+	w.handles = append(w.handles, handle)
+	w.asleep = append(w.asleep, false)
+	w.index[id] = handle.row
+	return handle
+}
+
+// Get returns the handle for an entity ID
+func (w *ECSWorld) Get(id string) (*ECSEntity, bool) {
+	row, ok := w.index[id]
+	if !ok {
+		return nil, false
+	}
+	return w.handles[row], true
+}
+
+// Remove deletes an entity by swapping the last row into its place.
+// Its handle stops being valid.
+func (w *ECSWorld) Remove(id string) bool {
+	row, ok := w.index[id]
+	if !ok {
+		return false
+	}
+	last := len(w.IDs) - 1
+	w.IDs[row] = w.IDs[last]
+	w.Types[row] = w.Types[last]
+	w.Positions[row] = w.Positions[last]
+	w.Velocities[row] = w.Velocities[last]
+	w.Masses[row] = w.Masses[last]
+	w.Shapes[row] = w.Shapes[last]
+	w.Renders[row] = w.Renders[last]
+	w.Animations[row] = w.Animations[last]
+	w.Layers[row] = w.Layers[last]
+	w.Frozen[row] = w.Frozen[last]
+	w.NoGravity[row] = w.NoGravity[last]
+	w.handles[row].row = -1
+	w.handles[row] = w.handles[last]
+	w.handles[row].row = row
+	w.asleep[row] = w.asleep[last]
+	w.index[w.IDs[row]] = row
+
+	w.IDs = w.IDs[:last]
+	w.Types = w.Types[:last]
+	w.Positions = w.Positions[:last]
+	w.Velocities = w.Velocities[:last]
+	w.Masses = w.Masses[:last]
+	w.Shapes = w.Shapes[:last]
+	w.Renders = w.Renders[:last]
+	w.Animations[last] = nil
+	w.Animations = w.Animations[:last]
+	w.Layers = w.Layers[:last]
+	w.Frozen = w.Frozen[:last]
+	w.NoGravity = w.NoGravity[:last]
+	w.handles[last] = nil
+	w.handles = w.handles[:last]
+	w.asleep = w.asleep[:last]
+	delete(w.index, id)
+	return true
+}
+
+// Entities returns the Entity facades of every row, for code written against Entity
+func (w *ECSWorld) Entities() []Entity {
+	entities := make([]Entity, len(w.handles))
+	for i, handle := range w.handles {
+		entities[i] = handle
+	}
+	return entities
+}
+
+// Step runs the physics systems once with the engine's settings. It matches
+// ApplyPhysics followed by HandleEntityCollisions for gravity, air resistance,
+// movement, walls, the material layer, the velocity cap and entity collisions, and
+// reports wall hits, sleep and collisions to pe.Lifecycle. Flocking and behaviors
+// stay on the Entity path.
+func (w *ECSWorld) Step(pe *PhysicsEngine) {
+	starts := append([]PositionComponent(nil), w.Positions...)
+	w.forceSystem(pe)
+	w.movementSystem(pe.DeltaTime)
+	edges := w.boundarySystem(pe)
+	w.materialSystem(pe)
+	w.velocityCapSystem(pe)
+	w.restSystem(pe, starts, edges)
+	w.collisionSystem(pe)
+}
+
+// forceSystem applies gravity and air resistance to unfrozen entities
+func (w *ECSWorld) forceSystem(pe *PhysicsEngine) {
+	gx, gy := pe.GravityVector()
+	gx, gy = gx*pe.DeltaTime, gy*pe.DeltaTime
+	for i := range w.Velocities {
+		v := &w.Velocities[i]
+		m := w.Masses[i]
+		if w.Frozen[i] {
+			v.VX, v.VY = 0, 0
+			continue
+		}
+		if m <= 0 {
+			continue
+		}
+		if !w.NoGravity[i] {
+			v.VX += gx / m
+			v.VY += gy / m
+		}
+		v.VX += -pe.AirResistance * v.VX / m
+		v.VY += -pe.AirResistance * v.VY / m
+	}
+}
+
+// movementSystem moves entities by their velocity and retargets their animation
+func (w *ECSWorld) movementSystem(deltaTime float64) {
+	for i := range w.Positions {
+		if w.Frozen[i] {
+			continue
+		}
+		p, v := &w.Positions[i], w.Velocities[i]
+		p.X += v.VX * deltaTime
+		p.Y += v.VY * deltaTime
+		if anim := w.Animations[i]; anim != nil {
+			anim.SetTarget(p.X, p.Y)
+		}
+	}
+}
+
+// boundarySystem bounces entities off the walls they collide with and returns the
+// walls each row hit
+func (w *ECSWorld) boundarySystem(pe *PhysicsEngine) [][]WallEdge {
+	edges := make([][]WallEdge, len(w.Positions))
+	for i := range w.Positions {
+		if w.Frozen[i] || !layersInteract(w.Layers[i][0], w.Layers[i][1], pe.WallCategory, pe.WallMask) {
+			continue
+		}
+		p, v, shape := &w.Positions[i], &w.Velocities[i], w.Shapes[i]
+
+		// Single-cell entities keep half their size inside the walls, as boundaryExtents does
+		halfWidth, halfHeight := float64(shape.Size)/2, float64(shape.Size)/2
+		if isMultiCell(shape.Size) {
+			halfWidth, halfHeight = shape.HalfWidth, shape.HalfHeight
+		}
+
+		if p.X-halfWidth <= pe.MinX {
+			p.X, v.VX = pe.MinX+halfWidth, -v.VX*pe.Restitution
+			edges[i] = append(edges[i], WallLeft)
+		} else if p.X+halfWidth >= pe.MaxX {
+			p.X, v.VX = pe.MaxX-halfWidth, -v.VX*pe.Restitution
+			edges[i] = append(edges[i], WallRight)
+		}
+		if p.Y-halfHeight <= pe.MinY {
+			p.Y, v.VY = pe.MinY+halfHeight, -v.VY*pe.Restitution
+			edges[i] = append(edges[i], WallTop)
+		} else if p.Y+halfHeight >= pe.MaxY {
+			p.Y, v.VY = pe.MaxY-halfHeight, -v.VY*pe.Restitution
+			edges[i] = append(edges[i], WallBottom)
+		}
+		if edges[i] != nil && w.Animations[i] != nil {
+			w.Animations[i].SetInitialPosition(p.X, p.Y) // Crisp bounce, as SetImmediatePosition does
+		}
+	}
+	return edges
+}
+
+// materialSystem bounces unfrozen rows off solid material cells. The material layer
+// is sparse next to the entities, so rows go through their handles.
+func (w *ECSWorld) materialSystem(pe *PhysicsEngine) {
+	if pe.Materials == nil {
+		return
+	}
+	for i, handle := range w.handles {
+		if !w.Frozen[i] {
+			pe.handleMaterialCollisions(handle)
+		}
+	}
+}
+
+// velocityCapSystem caps speeds and stops entities that are nearly at rest
+func (w *ECSWorld) velocityCapSystem(pe *PhysicsEngine) {
+	for i := range w.Velocities {
+		if w.Frozen[i] {
+			continue
+		}
+		v := &w.Velocities[i]
+		if math.Abs(v.VX) > pe.MaxVelocity {
+			v.VX = math.Copysign(pe.MaxVelocity, v.VX)
+		}
+		if math.Abs(v.VY) > pe.MaxVelocity {
+			v.VY = math.Copysign(pe.MaxVelocity, v.VY)
+		}
+		if math.Sqrt(v.VX*v.VX+v.VY*v.VY) < pe.MinVelocity*3 {
+			v.VX *= pe.StaticFriction
+			v.VY *= pe.StaticFriction
+		}
+		if math.Abs(v.VX) < pe.MinVelocity {
+			v.VX = 0
+		}
+		if math.Abs(v.VY) < pe.MinVelocity {
+			v.VY = 0
+		}
+	}
+}
+
+// restSystem reports wall hits and sleep for unfrozen rows, as ApplyPhysics does.
+// Resting rows press against the floor every step, so only moving ones hit walls.
+func (w *ECSWorld) restSystem(pe *PhysicsEngine, starts []PositionComponent, edges [][]WallEdge) {
+	for i := range w.Positions {
+		wasAsleep := w.asleep[i]
+		w.asleep[i] = false
+		if w.Frozen[i] {
+			continue
+		}
+		if !wasAsleep {
+			for _, edge := range edges[i] {
+				pe.Lifecycle.hitWall(w.handles[i], edge)
+			}
+		}
+		p := w.Positions[i]
+		if math.Hypot(p.X-starts[i].X, p.Y-starts[i].Y) < sleepDistance {
+			w.asleep[i] = true
+			if !wasAsleep {
+				pe.Lifecycle.slept(w.handles[i])
+			}
+		}
+	}
+}
+
+// bounds returns a row's collision bounds the way GetBounds computes them
+func (w *ECSWorld) bounds(row int) (minX, minY, maxX, maxY float64) {
+	p, shape := w.Positions[row], w.Shapes[row]
+	minX, minY = p.X-shape.HalfWidth, p.Y-shape.HalfHeight
+	return minX, minY, minX + shape.HalfWidth*2, minY + shape.HalfHeight*2
+}
+
+// collisionSystem finds touching pairs with a spatial hash over the rows' bounds and
+// resolves them in row order, as HandleEntityCollisions does. Detection reads the
+// component slices; the pairs that touch are resolved through their handles so
+// impulses and collision events match the Entity path.
+func (w *ECSWorld) collisionSystem(pe *PhysicsEngine) {
+	buckets := make(map[spatialKey][]int)
+	for i := range w.Positions {
+		minX, minY, maxX, maxY := w.bounds(i)
+		lo, hi := spatialKeyAt(minX, minY), spatialKeyAt(maxX, maxY)
+		for ky := lo.Y; ky <= hi.Y; ky++ {
+			for kx := lo.X; kx <= hi.X; kx++ {
+				key := spatialKey{kx, ky}
+				buckets[key] = append(buckets[key], i)
+			}
+		}
+	}
+
+	var pairs [][2]int
+	visited := make([]int, len(w.Positions)) // Last row, plus one, that tested each row
+	var later []int
+	for i := range w.Positions {
+		minX, minY, maxX, maxY := w.bounds(i)
+		lo, hi := spatialKeyAt(minX, minY), spatialKeyAt(maxX, maxY)
+		later = later[:0]
+		for ky := lo.Y; ky <= hi.Y; ky++ {
+			for kx := lo.X; kx <= hi.X; kx++ {
+				for _, j := range buckets[spatialKey{kx, ky}] {
+					if j <= i || visited[j] == i+1 {
+						continue
+					}
+					visited[j] = i + 1
+					x0, y0, x1, y1 := w.bounds(j)
+					if x0 <= maxX && x1 >= minX && y0 <= maxY && y1 >= minY {
+						later = append(later, j)
+					}
+				}
+			}
+		}
+		sort.Ints(later)
+
+		for _, j := range later {
+			if layersInteract(w.Layers[i][0], w.Layers[i][1], w.Layers[j][0], w.Layers[j][1]) && w.touching(pe, i, j) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+
+	for _, pair := range pairs {
+		pe.resolveCollision(w.handles[pair[0]], w.handles[pair[1]])
+	}
+}
+
+// touching is checkEntityCollision for two rows
+func (w *ECSWorld) touching(pe *PhysicsEngine, i, j int) bool {
+	p1, p2 := w.Positions[i], w.Positions[j]
+	dx, dy := p2.X-p1.X, p2.Y-p1.Y
+	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance == 0 {
+		return true
+	}
+	nx, ny := dx/distance, dy/distance
+	s1, s2 := w.Shapes[i], w.Shapes[j]
+	minDistance := shapeExtent(s1.Kind, s1.HalfWidth, s1.HalfHeight, nx, ny) +
+		shapeExtent(s2.Kind, s2.HalfWidth, s2.HalfHeight, nx, ny) - pe.ContactTolerance
+	return distance < minDistance
+}
+
+// ECSEntity is a handle to a row of an ECSWorld that implements Entity
+type ECSEntity struct {
+	world *ECSWorld
+	row   int // -1 once removed
+}
+
+// Valid reports whether the entity is still in its world
+func (h *ECSEntity) Valid() bool {
+	return h.row >= 0
+}
+
+func (h *ECSEntity) GetPosition() (float64, float64) {
+	p := h.world.Positions[h.row]
+	return p.X, p.Y
+}
+
+func (h *ECSEntity) GetDisplayPosition() (float64, float64) {
+	if anim := h.world.Animations[h.row]; anim != nil {
+		return anim.GetDisplayPosition()
+	}
+	return h.GetPosition()
+}
+
+func (h *ECSEntity) SetPosition(x, y float64) {
+	h.world.Positions[h.row] = PositionComponent{x, y}
+}
+
+func (h *ECSEntity) SetImmediatePosition(x, y float64) {
+	h.world.Positions[h.row] = PositionComponent{x, y}
+	if anim := h.world.Animations[h.row]; anim != nil {
+		anim.SetInitialPosition(x, y)
+	}
+}
+
+func (h *ECSEntity) GetVelocity() (float64, float64) {
+	v := h.world.Velocities[h.row]
+	return v.VX, v.VY
+}
+
+func (h *ECSEntity) SetVelocity(vx, vy float64) {
+	if math.IsInf(vx, 0) || math.IsNaN(vx) {
+		vx = 0
+	}
+	if math.IsInf(vy, 0) || math.IsNaN(vy) {
+		vy = 0
+	}
+	h.world.Velocities[h.row] = VelocityComponent{vx, vy}
+}
+
+func (h *ECSEntity) GetSymbol() string {
+	return h.world.Renders[h.row].Symbol
+}
+
+func (h *ECSEntity) GetColor() lipgloss.Color {
+	return h.world.Renders[h.row].Color
+}
+
+func (h *ECSEntity) SetColor(color lipgloss.Color) {
+	h.world.Renders[h.row].Color = color
+}
+
+func (h *ECSEntity) GetSize() int {
+	return h.world.Shapes[h.row].Size
+}
+
+func (h *ECSEntity) GetType() EntityType {
+	return h.world.Types[h.row]
+}
+
+func (h *ECSEntity) GetID() string {
+	return h.world.IDs[h.row]
+}
+
+func (h *ECSEntity) GetMass() float64 {
+	return h.world.Masses[h.row]
+}
+
+func (h *ECSEntity) SetMass(mass float64) {
+	h.world.Masses[h.row] = mass
+}
+
+func (h *ECSEntity) ApplyForce(fx, fy float64) {
+	if m := h.world.Masses[h.row]; m > 0 {
+		v := &h.world.Velocities[h.row]
+		v.VX += fx / m
+		v.VY += fy / m
+	}
+}
+
+func (h *ECSEntity) Update(deltaTime float64) {
+	p, v := &h.world.Positions[h.row], h.world.Velocities[h.row]
+	p.X += v.VX * deltaTime
+	p.Y += v.VY * deltaTime
+	if anim := h.world.Animations[h.row]; anim != nil {
+		anim.SetTarget(p.X, p.Y)
+	}
+}
+
+func (h *ECSEntity) GetAnimationState() *EntityAnimationState {
+	return h.world.Animations[h.row]
+}
+
+func (h *ECSEntity) UpdateAnimation(ae *AnimationEngine) {
+	if anim := h.world.Animations[h.row]; anim != nil {
+		ae.UpdateAnimation(anim)
+	}
+}
+
+func (h *ECSEntity) GetBounds() (x, y, width, height float64) {
+	p, shape := h.world.Positions[h.row], h.world.Shapes[h.row]
+	return p.X - shape.HalfWidth, p.Y - shape.HalfHeight, shape.HalfWidth * 2, shape.HalfHeight * 2
+}
+
+func (h *ECSEntity) CheckCollision(other Entity) bool {
+	if !shouldCollide(h, other) {
+		return false
+	}
+	x1, y1, w1, h1 := h.GetBounds()
+	x2, y2, w2, h2 := other.GetBounds()
+	return !(x1+w1 < x2 || x2+w2 < x1 || y1+h1 < y2 || y2+h2 < y1)
+}
+
+func (h *ECSEntity) GetCollisionLayers() (category, mask CollisionLayer) {
+	layers := h.world.Layers[h.row]
+	return layers[0], layers[1]
+}
+
+func (h *ECSEntity) IgnoresGravity() bool {
+	return h.world.NoGravity[h.row]
+}
+
+func (h *ECSEntity) IsFrozen() bool {
+	return h.world.Frozen[h.row]
+}
+
+func (h *ECSEntity) SetFrozen(frozen bool) {
+	h.world.Frozen[h.row] = frozen
+	if frozen {
+		h.world.Velocities[h.row] = VelocityComponent{}
+	}
+}
+
+func (h *ECSEntity) Render() Cell {
+	r := h.world.Renders[h.row]
+	cell := Cell{Glyph: r.Symbol, Color: r.Color, Bold: true, Faint: h.world.Layers[h.row][0] == LayerGhost}
+	if glyph, ok := glyphFor(h.GetType(), h.GetSize()); ok {
+		cell.Glyph = glyph
+	}
+	return cell
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newParityEntities builds the same mix of entities for the Entity and ECS paths
+func newParityEntities(count int) []Entity {
+	entities := make([]Entity, count)
+	for i := range entities {
+		x := float64(i%90) + 5.0
+		y := float64((i/10)%90) + 5.0
+		switch i % 3 {
+		case 0:
+			entities[i] = NewSphere(x, y, 1+i%4, lipgloss.Color("32"))
+		case 1:
+			entities[i] = NewSprite(x, y, 1+i%5, lipgloss.Color("32"), "★")
+		default:
+			entities[i] = NewSphere(x, y, 5, lipgloss.Color("32")) // Multi-cell
+		}
+		entities[i].SetVelocity(float64(i%10)-5.0, float64((i/2)%10)-5.0)
+	}
+	return entities
+}
+
+// countEvents observes an engine's lifecycle events by kind
+func countEvents(pe *PhysicsEngine) map[string]int {
+	counts := map[string]int{}
+	pe.Lifecycle = NewLifecycle()
+	pe.Lifecycle.Observe(LifecycleObserver{
+		OnCollide: func(entity, other Entity, impulse float64) { counts["collide"]++ },
+		OnWallHit: func(entity Entity, edge WallEdge) { counts["wall"]++ },
+		OnSleep:   func(entity Entity) { counts["sleep"]++ },
+	})
+	return counts
+}
+
+func TestECSWorldMatchesApplyPhysics(t *testing.T) {
+	newEngine := func() *PhysicsEngine {
+		pe := NewPhysicsEngine(100, 100)
+		pe.Materials = NewMaterialGrid(100, 100)
+		for x := 20; x < 60; x++ {
+			pe.Materials.Set(x, 70, MaterialStone)
+		}
+		return pe
+	}
+	entityEngine, ecsEngine := newEngine(), newEngine()
+	entityEvents, ecsEvents := countEvents(entityEngine), countEvents(ecsEngine)
+	entities := newParityEntities(60)
+	world := NewECSWorld()
+	for _, entity := range newParityEntities(60) {
+		world.Add(entity)
+	}
+
+	for step := 0; step < 200; step++ {
+		entityEngine.ApplyPhysics(entities)
+		entityEngine.HandleEntityCollisions(entities)
+		world.Step(ecsEngine)
+	}
+
+	for i, handle := range world.Entities() {
+		x1, y1 := entities[i].GetPosition()
+		x2, y2 := handle.GetPosition()
+		vx1, vy1 := entities[i].GetVelocity()
+		vx2, vy2 := handle.GetVelocity()
+		if math.Abs(x1-x2) > 1e-9 || math.Abs(y1-y2) > 1e-9 || math.Abs(vx1-vx2) > 1e-9 || math.Abs(vy1-vy2) > 1e-9 {
+			t.Fatalf("Entity %d diverged: Entity path (%.3f, %.3f) v(%.3f, %.3f), ECS (%.3f, %.3f) v(%.3f, %.3f)",
+				i, x1, y1, vx1, vy1, x2, y2, vx2, vy2)
+		}
+	}
+	if entityEvents["collide"] == 0 || entityEvents["wall"] == 0 || entityEvents["sleep"] == 0 {
+		t.Fatalf("Expected the parity run to collide, hit walls and sleep, got %v", entityEvents)
+	}
+	for kind, count := range entityEvents {
+		if ecsEvents[kind] != count {
+			t.Errorf("Expected %d %s events from the world, got %d", count, kind, ecsEvents[kind])
+		}
+	}
+}
+
+func TestECSWorldRemoveKeepsHandles(t *testing.T) {
+	world := NewECSWorld()
+	a, _ := world.Spawn(SphereType, 1, 1, 1, lipgloss.Color("32"))
+	b, _ := world.Spawn(SpriteType, 2, 2, 1, lipgloss.Color("32"))
+	c, _ := world.Spawn(BoidType, 3, 3, 1, lipgloss.Color("32"))
+
+	if !world.Remove(a.GetID()) || world.Len() != 2 {
+		t.Fatal("Expected the entity to be removed")
+	}
+	if a.Valid() {
+		t.Error("Expected the removed entity's handle to be invalid")
+	}
+	if x, _ := c.GetPosition(); x != 3 || c.GetType() != BoidType {
+		t.Error("Expected the moved row's handle to follow it")
+	}
+	if handle, ok := world.Get(b.GetID()); !ok || handle != b {
+		t.Error("Expected lookups by ID to survive removals")
+	}
+	if !c.IgnoresGravity() {
+		t.Error("Expected boids to keep ignoring gravity in the world")
+	}
+}
+
+func TestECSEntityFacade(t *testing.T) {
+	pe := NewPhysicsEngine(100, 100)
+	world := NewECSWorld()
+	left, _ := world.Spawn(SphereType, 10, 10, 1, lipgloss.Color("32"))
+	right, _ := world.Spawn(SphereType, 10.5, 10, 1, lipgloss.Color("31"))
+	left.SetVelocity(5, 0)
+	right.SetVelocity(-5, 0)
+
+	// Code written against Entity works on world rows
+	pe.HandleEntityCollisions(world.Entities())
+	if vx, _ := left.GetVelocity(); vx >= 0 {
+		t.Error("Expected the collision to bounce the left entity back")
+	}
+	if left.Render().Glyph != "●" {
+		t.Error("Expected the facade to render the registered glyph")
+	}
+
+	// An entity's own mass moves into the world with it
+	heavy := NewSphere(30, 10, 1, lipgloss.Color("33"))
+	heavy.SetMass(12)
+	if moved := world.Add(heavy); moved.GetMass() != 12 {
+		t.Errorf("Expected the world to keep the entity's mass, got %.1f", moved.GetMass())
+	}
+
+	left.SetFrozen(true)
+	frozenX, frozenY := left.GetPosition()
+	world.Step(pe)
+	if x, y := left.GetPosition(); x != frozenX || y != frozenY {
+		t.Error("Expected frozen rows to stay put")
+	}
+}
+
+func BenchmarkApplyPhysicsVsECS(b *testing.B) {
+	for _, count := range []int{100, 500, 2000} {
+		b.Run(fmt.Sprintf("ApplyPhysicsAndCollisions/%d", count), func(b *testing.B) {
+			pe := NewPhysicsEngine(100, 100)
+			entities := newParityEntities(count)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pe.ApplyPhysics(entities)
+				pe.HandleEntityCollisions(entities)
+			}
+		})
+		b.Run(fmt.Sprintf("ECSWorldStep/%d", count), func(b *testing.B) {
+			pe := NewPhysicsEngine(100, 100)
+			world := NewECSWorld()
+			for _, entity := range newParityEntities(count) {
+				world.Add(entity)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				world.Step(pe)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Impact effect tuning. Impulses are the magnitudes reported by OnCollide; wall hits
+// use the entity's speed as it bounces.
+const (
+	flashImpulse  = 1.0  // Weakest collision that makes both entities flash
+	sparkImpulse  = 8.0  // Weakest collision that throws sparks
+	squashSpeed   = 8.0  // Slowest wall bounce that squashes the entity
+	maxSparks     = 200  // Sparks beyond this are not spawned
+	sparkSpeed    = 12.0 // Fastest spark, in cells per second
+	sparksPerSide = 3    // Sparks thrown for each entity in a hard collision
+
+	flashDuration  = 150 * time.Millisecond
+	squashDuration = 120 * time.Millisecond
+	sparkLifetime  = 300 * time.Millisecond
+)
+
+// Spark is a short-lived cosmetic particle. Sparks never collide with anything.
+type Spark struct {
+	X, Y   float64
+	VX, VY float64
+	Life   time.Duration // Time left before it disappears
+}
+
+// ImpactEffects turns collision impulses into flashes, squashed glyphs and sparks.
+// The effects are cosmetic: they never change physics. Entity flashes and squashes
+// count down in each entity's EntityAnimationState; sparks live here. Both are
+// advanced by the AnimationEngine's clock.
+type ImpactEffects struct {
+	Enabled bool // Off for low-power terminals: no new effects and no sparks
+	Sparks  []Spark
+
+	lastUpdate time.Time
+}
+
+// NewImpactEffects creates an enabled effects layer
+func NewImpactEffects() *ImpactEffects {
+	return &ImpactEffects{Enabled: true}
+}
+
+// Observer returns the lifecycle observer that starts effects from impacts
+func (fx *ImpactEffects) Observer() LifecycleObserver {
+	return LifecycleObserver{
+		OnCollide: func(entity, other Entity, impulse float64) {
+			if !fx.Enabled || impulse < flashImpulse {
+				return
+			}
+			if state := entity.GetAnimationState(); state != nil {
+				state.Flash = flashDuration
+			}
+			if impulse >= sparkImpulse {
+				// Each entity of the pair hears the collision, so each throws half the burst
+				x1, y1 := entity.GetPosition()
+				x2, y2 := other.GetPosition()
+				fx.burst((x1+x2)/2, (y1+y2)/2, sparksPerSide)
+			}
+		},
+		OnWallHit: func(entity Entity, edge WallEdge) {
+			if !fx.Enabled {
+				return
+			}
+			vx, vy := entity.GetVelocity()
+			if math.Hypot(vx, vy) < squashSpeed {
+				return
+			}
+			if state := entity.GetAnimationState(); state != nil {
+				state.Squash, state.SquashEdge = squashDuration, edge
+			}
+		},
+	}
+}
+
+// burst throws sparks out from (x, y) in random directions
+func (fx *ImpactEffects) burst(x, y float64, count int) {
+	for i := 0; i < count && len(fx.Sparks) < maxSparks; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := sparkSpeed * (0.5 + rand.Float64()/2)
+		fx.Sparks = append(fx.Sparks, Spark{
+			X: x, Y: y,
+			VX: math.Cos(angle) * speed, VY: math.Sin(angle) * speed,
+			Life: sparkLifetime,
+		})
+	}
+}
+
+// Update moves sparks and removes expired ones, stepping by the time the
+// animation engine's clock says has passed since the last update
+func (fx *ImpactEffects) Update(ae *AnimationEngine) {
+	now := ae.now()
+	if fx.lastUpdate.IsZero() {
+		fx.lastUpdate = now
+	}
+	step := ae.frameStep(now, fx.lastUpdate)
+	fx.lastUpdate = now
+
+	if !fx.Enabled {
+		fx.Sparks = nil
+		return
+	}
+	live := fx.Sparks[:0]
+	for _, spark := range fx.Sparks {
+		spark.Life -= step
+		if spark.Life <= 0 {
+			continue
+		}
+		spark.X += spark.VX * step.Seconds()
+		spark.Y += spark.VY * step.Seconds()
+		live = append(live, spark)
+	}
+	fx.Sparks = live
+}
+
+// SetEnabled turns effects on or off; turning them off clears any in progress
+func (fx *ImpactEffects) SetEnabled(enabled bool, entities []Entity) {
+	fx.Enabled = enabled
+	if enabled {
+		return
+	}
+	fx.Sparks = nil
+	for _, entity := range entities {
+		if state := entity.GetAnimationState(); state != nil {
+			state.Flash, state.Squash = 0, 0
+		}
+	}
+}
+
+// squashGlyphs are drawn in place of a single-cell entity just after a hard wall hit,
+// flattened against the wall it hit
+var squashGlyphs = [...]string{WallLeft: "▮", WallRight: "▮", WallTop: "▬", WallBottom: "▬"}
+
+// applyImpactEffects restyles an entity's drawn cell for any running flash or squash.
+// Squashing only applies to single-cell entities, which pass single as true.
+func applyImpactEffects(entity Entity, cell Cell, single bool) Cell {
+	state := entity.GetAnimationState()
+	if state == nil || (state.Flash <= 0 && state.Squash <= 0) {
+		return cell
+	}
+	color := entity.GetColor()
+	restyled := Cell{Glyph: cell.Glyph, Color: color}
+	if single && state.Squash > 0 {
+		restyled.Glyph = squashGlyphs[state.SquashEdge]
+	}
+	if state.Flash > 0 {
+		restyled.Color = blendColors(color, lipgloss.Color("#FFFFFF"), 0.6)
+		restyled.Bold = true
+	}
+	return restyled
+}
+
+// sparkGlyph fades a spark as its life runs out
+func sparkGlyph(life time.Duration) string {
+	switch {
+	case life > sparkLifetime*2/3:
+		return "✦"
+	case life > sparkLifetime/3:
+		return "*"
+	default:
+		return "·"
+	}
+}
+
+// drawSparks draws sparks into the effects layer where nothing else was drawn
+func drawSparks(comp *Compositor, cam Camera, sparks []Spark) {
+	effects := comp.Layer(LayerEffects)
+	for _, spark := range sparks {
+		x, y := cam.ToScreen(spark.X, spark.Y)
+		if y >= 0 && y < comp.Height && x >= 0 && x < comp.Width && !comp.Occupied(x, y) {
+			effects[y][x] = Cell{Glyph: sparkGlyph(spark.Life), Color: lipgloss.Color("#FFD27F")}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// collidePair runs one head-on collision at the given closing speed through a
+// lifecycle observed by fx
+func collidePair(fx *ImpactEffects, speed float64) (*Sphere, *Sphere) {
+	pe := NewPhysicsEngine(80, 40)
+	pe.Lifecycle = NewLifecycle()
+	pe.Lifecycle.Observe(fx.Observer())
+	left, right := NewSphere(10, 10, 1, lipgloss.Color("#00FF00")), NewSphere(10.5, 10, 1, lipgloss.Color("#0000FF"))
+	left.SetVelocity(speed/2, 0)
+	right.SetVelocity(-speed/2, 0)
+	pe.HandleEntityCollisions([]Entity{left, right})
+	return left, right
+}
+
+func TestImpactFlashesAndSparks(t *testing.T) {
+	fx := NewImpactEffects()
+	left, right := collidePair(fx, 30)
+	if left.AnimationState.Flash <= 0 || right.AnimationState.Flash <= 0 {
+		t.Error("Expected both entities of a collision to flash")
+	}
+	if len(fx.Sparks) != 2*sparksPerSide {
+		t.Errorf("Expected a hard collision to throw %d sparks, got %d", 2*sparksPerSide, len(fx.Sparks))
+	}
+
+	gentle := NewImpactEffects()
+	collidePair(gentle, 4)
+	if len(gentle.Sparks) != 0 {
+		t.Error("Expected a gentle collision to throw no sparks")
+	}
+
+	off := NewImpactEffects()
+	off.Enabled = false
+	left, _ = collidePair(off, 30)
+	if left.AnimationState.Flash != 0 || len(off.Sparks) != 0 {
+		t.Error("Expected disabled effects to ignore impacts")
+	}
+}
+
+func TestImpactEffectsExpireWithAnimationClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = clock.Now
+
+	fx := NewImpactEffects()
+	fx.Update(ae)
+	fx.burst(5, 5, 4)
+	sphere := NewSphere(5, 5, 1, lipgloss.Color("#00FF00"))
+	sphere.UpdateAnimation(ae) // Starts the sphere on the engine's clock
+	sphere.AnimationState.Flash = flashDuration
+
+	clock.Advance(50 * time.Millisecond)
+	fx.Update(ae)
+	sphere.UpdateAnimation(ae)
+	if len(fx.Sparks) != 4 || sphere.AnimationState.Flash != flashDuration-50*time.Millisecond {
+		t.Fatal("Expected effects to count down by the elapsed time")
+	}
+	if x, y := fx.Sparks[0].X, fx.Sparks[0].Y; x == 5 && y == 5 {
+		t.Error("Expected sparks to fly outward")
+	}
+
+	for i := 0; i < 5; i++ {
+		clock.Advance(100 * time.Millisecond)
+		fx.Update(ae)
+		sphere.UpdateAnimation(ae)
+	}
+	if len(fx.Sparks) != 0 || sphere.AnimationState.Flash != 0 {
+		t.Error("Expected sparks and flashes to expire")
+	}
+}
+
+func TestWallSquashDrawnAndToggled(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+
+	// A fast fall into the floor squashes the ball against it
+	ball := NewSphere(20, float64(model.physicsEngine.MaxY)-1, 1, lipgloss.Color("#00FF00"))
+	ball.SetVelocity(0, 40)
+	model.entityManager.AddEntity(ball)
+	model.physicsEngine.ApplyPhysics([]Entity{ball})
+	if ball.AnimationState.Squash <= 0 || ball.AnimationState.SquashEdge != WallBottom {
+		t.Fatal("Expected a hard floor hit to squash the ball against the bottom wall")
+	}
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	model.renderSimulation()
+	if _, _, ok := buffer.Find("▬"); !ok {
+		t.Error("Expected the squashed glyph to be drawn")
+	}
+
+	model.effects.burst(10.5, 3.5, 1)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	model = updatedModel.(Model)
+	if model.effects.Enabled || len(model.effects.Sparks) != 0 || ball.AnimationState.Squash != 0 {
+		t.Error("Expected F to turn effects off and clear those in progress")
+	}
+	if !strings.Contains(model.renderSimulation(), "Effects off") {
+		t.Error("Expected the status line to show effects are off")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -49,7 +50,7 @@ type Entity interface {
 	CheckCollision(other Entity) bool
 
 	// Rendering
-	Render() string
+	Render() Cell
 }
 
 // BaseEntity provides common functionality for all entities
@@ -62,9 +63,29 @@ type BaseEntity struct {
 	Symbol string
 	Type   EntityType
 	Mass   float64
+	ZOrder int // Drawing order: higher is drawn over lower
+
+	// Set by EntityManager.AddEntity from a counter that only goes up; entities
+	// with the same ZOrder are drawn in spawn order
+	SpawnOrder int
 
 	// Animation state
 	AnimationState *EntityAnimationState
+
+	// Optional per-entity logic run each tick
+	Behavior Behavior
+
+	// Collision filtering: the categories this entity belongs to and the
+	// categories it collides with. Left zero, they are LayerDefault and LayerAll.
+	CollisionCategory CollisionLayer
+	CollisionMask     CollisionLayer
+
+	// User-defined grouping: tags in the order they were added, and key/value metadata
+	Tags     []string
+	Metadata map[string]string
+
+	// Frozen entities hold still until unfrozen
+	Frozen bool
 }
 
 // Position methods
@@ -84,6 +105,15 @@ func (e *BaseEntity) SetImmediatePosition(x, y float64) {
 	}
 }
 
+// teleport moves an entity at once to a position it did not reach by moving, such
+// as a portal's exit or an edited position, leaving no trail behind
+func teleport(entity Entity, x, y float64) {
+	entity.SetImmediatePosition(x, y)
+	if state := entity.GetAnimationState(); state != nil {
+		state.Teleport(x, y)
+	}
+}
+
 func (e *BaseEntity) GetVelocity() (float64, float64) {
 	return e.VX, e.VY
 }
@@ -108,10 +138,44 @@ func (e *BaseEntity) GetColor() lipgloss.Color {
 	return e.Color
 }
 
+func (e *BaseEntity) SetColor(color lipgloss.Color) {
+	e.Color = color
+}
+
 func (e *BaseEntity) GetSize() int {
 	return e.Size
 }
 
+// SetSize resizes the entity in place; mass follows the new size
+func (e *BaseEntity) SetSize(size int) {
+	e.Size = size
+	e.Mass = massFor(e.Type, size)
+}
+
+func (e *BaseEntity) GetMass() float64 {
+	return e.Mass
+}
+
+func (e *BaseEntity) SetMass(mass float64) {
+	e.Mass = mass
+}
+
+func (e *BaseEntity) GetZOrder() int {
+	return e.ZOrder
+}
+
+func (e *BaseEntity) SetZOrder(z int) {
+	e.ZOrder = z
+}
+
+func (e *BaseEntity) GetSpawnOrder() int {
+	return e.SpawnOrder
+}
+
+func (e *BaseEntity) SetSpawnOrder(order int) {
+	e.SpawnOrder = order
+}
+
 // Entity properties
 func (e *BaseEntity) GetType() EntityType {
 	return e.Type
@@ -121,6 +185,11 @@ func (e *BaseEntity) GetID() string {
 	return e.ID
 }
 
+// SetID replaces the entity's ID; the EntityManager assigns IDs when entities are added
+func (e *BaseEntity) SetID(id string) {
+	e.ID = id
+}
+
 // Physics
 func (e *BaseEntity) ApplyForce(fx, fy float64) {
 	// F = ma, so a = F/m
@@ -143,26 +212,23 @@ func (e *BaseEntity) Update(deltaTime float64) {
 
 // Collision detection
 func (e *BaseEntity) GetBounds() (x, y, width, height float64) {
+	// Multi-cell entities collide with the shape they draw
+	if isMultiCell(e.Size) {
+		return multiCellBounds(e.X, e.Y, e.Size)
+	}
+
 	// Adjust collision size to better match visual representation
 	// Smaller collision boxes for single-character entities
-	var effectiveSize float64
-	switch e.Size {
-	case 1:
-		effectiveSize = 0.8 // Tiny
-	case 2:
-		effectiveSize = 1.0 // Small
-	case 3:
-		effectiveSize = 1.3 // Medium
-	case 4:
-		effectiveSize = 1.6 // Large
-	default:
-		effectiveSize = float64(e.Size) * 0.8
-	}
+	effectiveSize := effectiveSizeFor(e.Size)
 
 	return e.X - effectiveSize/2, e.Y - effectiveSize/2, effectiveSize, effectiveSize
 }
 
 func (e *BaseEntity) CheckCollision(other Entity) bool {
+	if !shouldCollide(e, other) {
+		return false
+	}
+
 	x1, y1, w1, h1 := e.GetBounds()
 	x2, y2, w2, h2 := other.GetBounds()
 
@@ -188,46 +254,52 @@ func (e *BaseEntity) UpdateAnimation(ae *AnimationEngine) {
 	}
 }
 
+// Collision layer methods. Entities built without layers collide like the built-in types.
+func (e *BaseEntity) GetCollisionLayers() (category, mask CollisionLayer) {
+	category, mask = e.CollisionCategory, e.CollisionMask
+	if category == LayerNone {
+		category = LayerDefault
+	}
+	if mask == LayerNone {
+		mask = LayerAll
+	}
+	return category, mask
+}
+
+func (e *BaseEntity) SetCollisionLayers(category, mask CollisionLayer) {
+	e.CollisionCategory, e.CollisionMask = category, mask
+}
+
+// MakeGhost moves the entity to the ghost layer so it passes through other
+// entities while still bouncing off walls and obstacles
+func (e *BaseEntity) MakeGhost() {
+	e.SetCollisionLayers(LayerGhost, ghostCollisionMask)
+}
+
+// IsGhost reports whether the entity is on the ghost layer
+func (e *BaseEntity) IsGhost() bool {
+	return e.CollisionCategory == LayerGhost
+}
+
+// Behavior methods
+func (e *BaseEntity) GetBehavior() Behavior {
+	return e.Behavior
+}
+
+func (e *BaseEntity) SetBehavior(behavior Behavior) {
+	e.Behavior = behavior
+}
+
 // Rendering with enhanced visual polish and effects
-func (e *BaseEntity) Render() string {
-	// Create enhanced style with better visibility
-	style := lipgloss.NewStyle().
-		Foreground(e.Color).
-		Bold(true)
-
-	// Create visual representation that matches collision size
-	switch e.Size {
-	case 1:
-		// Tiny entities - single character
-		if e.Type == SphereType {
-			return style.Render("●") // Small filled circle
-		} else {
-			return style.Render("◆") // Small diamond
-		}
-	case 2:
-		// Small entities - single character but different symbol
-		if e.Type == SphereType {
-			return style.Render("⬤") // Medium filled circle
-		} else {
-			return style.Render("◉") // Medium diamond with dot
-		}
-	case 3:
-		// Medium entities - larger visual symbols
-		if e.Type == SphereType {
-			return style.Render("⭘") // Large circle with ring
-		} else {
-			return style.Render("⬢") // Large hexagon
-		}
-	case 4:
-		// Large entities - biggest symbols
-		if e.Type == SphereType {
-			return style.Render("⬢") // Extra large hexagon
-		} else {
-			return style.Render("⬛") // Large black square
-		}
-	default:
-		return style.Render(e.Symbol)
+func (e *BaseEntity) Render() Cell {
+	// Bold for better visibility; ghosts are drawn dimmed
+	cell := Cell{Glyph: e.Symbol, Color: e.Color, Bold: true, Faint: e.IsGhost()}
+
+	// Registered types draw a glyph that matches their collision size
+	if glyph, ok := glyphFor(e.Type, e.Size); ok {
+		cell.Glyph = glyph
 	}
+	return cell
 }
 
 // Sphere represents a circular entity
@@ -236,6 +308,35 @@ type Sphere struct {
 	Radius float64
 }
 
+func init() {
+	mustRegisterEntityType(EntityTypeInfo{
+		Type:     SphereType,
+		Name:     "Sphere",
+		Icon:     "●",
+		Key:      "a",
+		Glyphs:   []string{"●", "⬤", "⭘", "⬢"}, // Small filled circle up to extra large hexagon
+		Material: RubberMaterial,
+		Shape:    ShapeEllipse,
+		New: func(x, y float64, size int, color lipgloss.Color) Entity {
+			return NewSphere(x, y, size, color)
+		},
+		Order: 10,
+	})
+	mustRegisterEntityType(EntityTypeInfo{
+		Type:     SpriteType,
+		Name:     "Sprite",
+		Icon:     "◆",
+		Key:      "s",
+		Glyphs:   []string{"◆", "◉", "⬢", "⬛"}, // Small diamond up to large square
+		Material: PlasticMaterial,
+		Shape:    ShapeBox,
+		New: func(x, y float64, size int, color lipgloss.Color) Entity {
+			return NewSprite(x, y, size, color, "") // Random symbol
+		},
+		Order: 20,
+	})
+}
+
 // NewSphere creates a new sphere entity
 func NewSphere(x, y float64, size int, color lipgloss.Color) *Sphere {
 	// Validate and sanitize size input
@@ -243,24 +344,10 @@ func NewSphere(x, y float64, size int, color lipgloss.Color) *Sphere {
 		size = 1 // Default to minimum valid size
 	}
 
-	// Create animation engine for this entity
-	animEngine := NewAnimationEngine()
-	animState := animEngine.NewEntityAnimationState(x, y)
+	animState := newEntityAnimationState(x, y)
 
 	// Calculate effective radius to match visual representation
-	var effectiveSize float64
-	switch size {
-	case 1:
-		effectiveSize = 0.8 // Tiny
-	case 2:
-		effectiveSize = 1.0 // Small
-	case 3:
-		effectiveSize = 1.3 // Medium
-	case 4:
-		effectiveSize = 1.6 // Large
-	default:
-		effectiveSize = float64(size) * 0.8
-	}
+	effectiveSize := effectiveSizeFor(size)
 
 	return &Sphere{
 		BaseEntity: BaseEntity{
@@ -273,8 +360,11 @@ func NewSphere(x, y float64, size int, color lipgloss.Color) *Sphere {
 			Color:          color,
 			Symbol:         "●",
 			Type:           SphereType,
-			Mass:           effectiveSize, // Mass proportional to effective size
+			Mass:           massFor(SphereType, size), // Mass proportional to effective size
 			AnimationState: animState,
+
+			CollisionCategory: LayerDefault,
+			CollisionMask:     LayerAll,
 		},
 		Radius: effectiveSize / 2.0,
 	}
@@ -299,8 +389,17 @@ func (s *Sphere) SetRadius(radius float64) {
 	}
 }
 
+// SetSize resizes the sphere and keeps its collision radius in step
+func (s *Sphere) SetSize(size int) {
+	s.BaseEntity.SetSize(size)
+	s.Radius = effectiveSizeFor(size) / 2.0
+}
+
 // Override GetBounds for circular collision using effective size
 func (s *Sphere) GetBounds() (x, y, width, height float64) {
+	if isMultiCell(s.Size) {
+		return multiCellBounds(s.X, s.Y, s.Size)
+	}
 	return s.X - s.Radius, s.Y - s.Radius, s.Radius * 2, s.Radius * 2
 }
 
@@ -310,6 +409,12 @@ type Sprite struct {
 	CustomSymbol string
 	Animation    []string
 	CurrentFrame int
+
+	// Clips, when set, animate the sprite by state with timed frames
+	Clips *ClipPlayer
+
+	// Color the sprite shows on frames that do not set their own
+	BaseColor lipgloss.Color
 }
 
 // NewSprite creates a new sprite entity
@@ -321,24 +426,7 @@ func NewSprite(x, y float64, size int, color lipgloss.Color, customSymbol string
 		symbol = symbols[rand.Intn(len(symbols))]
 	}
 
-	// Create animation engine for this entity
-	animEngine := NewAnimationEngine()
-	animState := animEngine.NewEntityAnimationState(x, y)
-
-	// Calculate effective size to match visual representation
-	var effectiveSize float64
-	switch size {
-	case 1:
-		effectiveSize = 0.8 // Tiny
-	case 2:
-		effectiveSize = 1.0 // Small
-	case 3:
-		effectiveSize = 1.3 // Medium
-	case 4:
-		effectiveSize = 1.6 // Large
-	default:
-		effectiveSize = float64(size) * 0.8
-	}
+	animState := newEntityAnimationState(x, y)
 
 	return &Sprite{
 		BaseEntity: BaseEntity{
@@ -351,20 +439,41 @@ func NewSprite(x, y float64, size int, color lipgloss.Color, customSymbol string
 			Color:          color,
 			Symbol:         symbol,
 			Type:           SpriteType,
-			Mass:           effectiveSize * 0.8, // Sprites are slightly lighter than spheres
+			Mass:           massFor(SpriteType, size), // Sprites are slightly lighter than spheres
 			AnimationState: animState,
+
+			CollisionCategory: LayerDefault,
+			CollisionMask:     LayerAll,
 		},
 		CustomSymbol: symbol,
 		Animation:    []string{symbol}, // Single frame by default
 		CurrentFrame: 0,
+		BaseColor:    color,
 	}
 }
 
+// NewAnimatedSprite creates a sprite of a sprite sheet type that plays the given clips
+func NewAnimatedSprite(x, y float64, size int, color lipgloss.Color, entityType EntityType, clips map[SpriteState]*AnimationClip) *Sprite {
+	s := NewSprite(x, y, size, color, clips[SpriteIdle].Frames[0].Symbol)
+	s.ID = generateID(string(entityType))
+	s.Type = entityType
+	s.Mass = massFor(entityType, size)
+	s.Clips = NewClipPlayer(clips)
+	s.applyFrame()
+	return s
+}
+
 // Sprite-specific methods
+
+// SetAnimation makes the sprite loop through frames, each shown for the default frame duration
 func (s *Sprite) SetAnimation(frames []string) {
 	if len(frames) > 0 {
 		s.Animation = frames
+		s.CurrentFrame = 0
 		s.Symbol = frames[0]
+		s.Clips = NewClipPlayer(map[SpriteState]*AnimationClip{
+			SpriteIdle: NewLoopClip(frames, defaultFrameDuration),
+		})
 	}
 }
 
@@ -375,49 +484,212 @@ func (s *Sprite) NextFrame() {
 	}
 }
 
+// SetColor changes the color shown on frames without their own color
+func (s *Sprite) SetColor(color lipgloss.Color) {
+	s.BaseColor = color
+	s.Color = color
+	if s.Clips != nil {
+		s.applyFrame()
+	}
+}
+
+// Hit restarts the sprite's hit clip, if it has one
+func (s *Sprite) Hit() {
+	if s.Clips != nil && s.Clips.Has(SpriteHit) {
+		s.Clips.restart(SpriteHit)
+		s.applyFrame()
+	}
+}
+
+// OnCollide plays the hit clip when the sprite collides with something
+func (s *Sprite) OnCollide(other Entity, impulse float64) {
+	s.Hit()
+}
+
 // Override Update to handle animation
 func (s *Sprite) Update(deltaTime float64) {
 	s.BaseEntity.Update(deltaTime)
-	// Animate every few updates (simplified)
-	if rand.Float64() < 0.1 { // 10% chance to animate per update
-		s.NextFrame()
+	if s.Clips == nil {
+		return
+	}
+
+	// A hit clip plays to its end before the sprite returns to idle or moving
+	if s.Clips.State != SpriteHit || s.Clips.Done() {
+		state := SpriteIdle
+		if math.Hypot(s.VX, s.VY) > spriteMovingSpeed && s.Clips.Has(SpriteMoving) {
+			state = SpriteMoving
+		}
+		s.Clips.Play(state)
 	}
+	s.Clips.Advance(deltaTime)
+	s.applyFrame()
 }
 
-// EntityManager manages a collection of entities with thread-safe operations
+// Render draws the current clip frame; sprites without clips use their registered glyph
+func (s *Sprite) Render() Cell {
+	if s.Clips == nil {
+		return s.BaseEntity.Render()
+	}
+	return Cell{Glyph: s.Symbol, Color: s.Color, Bold: true, Faint: s.IsGhost()}
+}
+
+// applyFrame shows the clip player's current frame
+func (s *Sprite) applyFrame() {
+	frame := s.Clips.Frame()
+	if frame.Symbol != "" {
+		s.Symbol = frame.Symbol
+	}
+	s.Color = s.BaseColor
+	if frame.Color != "" {
+		s.Color = frame.Color
+	}
+	s.CurrentFrame = s.Clips.FrameIndex()
+}
+
+// EntityManager manages a collection of entities with thread-safe operations.
+// Entities get a monotonic ID when added and can be looked up by ID in constant time.
 type EntityManager struct {
-	mu       sync.RWMutex // Protects entities slice from concurrent access
+	mu       sync.RWMutex // Protects entities slice and index from concurrent access
 	entities []Entity
+	index    map[string]int // Entity ID -> position in entities
 	nextID   int
+	spawned  int // Entities ever added, for their spawn order
+
+	// Acceleration structure for spatial queries; nil when it needs rebuilding
+	spatial *spatialHash
+
+	// Receives spawn and despawn events; share it with the physics engine for the rest
+	Lifecycle *Lifecycle
 }
 
 // NewEntityManager creates a new entity manager
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
-		entities: make([]Entity, 0),
-		nextID:   1,
+		entities:  make([]Entity, 0),
+		index:     make(map[string]int),
+		nextID:    1,
+		Lifecycle: NewLifecycle(),
 	}
 }
 
-// AddEntity adds an entity to the manager (thread-safe)
+// AddEntity adds an entity to the manager and assigns it the next ID (thread-safe).
+// IDs are never reused, even after Clear, so they stay valid as references.
+// Adding an entity that is already managed does nothing. OnSpawn fires once it is added.
 func (em *EntityManager) AddEntity(entity Entity) {
 	em.mu.Lock()
-	defer em.mu.Unlock()
+	if i, exists := em.index[entity.GetID()]; exists && em.entities[i] == entity {
+		em.mu.Unlock()
+		return
+	}
+	if setter, ok := entity.(interface{ SetID(string) }); ok {
+		setter.SetID(fmt.Sprintf("%s_%d", entity.GetType(), em.nextID))
+		em.nextID++
+	}
+	em.spawned++
+	if setter, ok := entity.(interface{ SetSpawnOrder(int) }); ok {
+		setter.SetSpawnOrder(em.spawned)
+	}
+
+	em.index[entity.GetID()] = len(em.entities)
 	em.entities = append(em.entities, entity)
+	em.spatial = nil
+	em.mu.Unlock()
+
+	em.Lifecycle.spawned(entity)
 }
 
-// RemoveEntity removes an entity by ID (thread-safe)
+// Get returns the entity with the given ID (thread-safe)
+func (em *EntityManager) Get(id string) (Entity, bool) {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	i, ok := em.index[id]
+	if !ok {
+		return nil, false
+	}
+	return em.entities[i], true
+}
+
+// RemoveEntity removes an entity by ID in constant time (thread-safe).
+// The last entity takes the removed entity's place, so removal does not preserve order.
+// OnDespawn fires once it is removed.
 func (em *EntityManager) RemoveEntity(id string) bool {
 	em.mu.Lock()
-	defer em.mu.Unlock()
-	for i, entity := range em.entities {
-		if entity.GetID() == id {
-			// Remove entity from slice
-			em.entities = append(em.entities[:i], em.entities[i+1:]...)
-			return true
+	entity, ok := em.removeLocked(id)
+	em.mu.Unlock()
+
+	if ok {
+		em.Lifecycle.despawned(entity)
+	}
+	return ok
+}
+
+// RemoveEntities removes every entity with one of the given IDs and returns how many were removed (thread-safe).
+// OnDespawn fires for each in the order of ids.
+func (em *EntityManager) RemoveEntities(ids ...string) int {
+	em.mu.Lock()
+	var removed []Entity
+	for _, id := range ids {
+		if entity, ok := em.removeLocked(id); ok {
+			removed = append(removed, entity)
 		}
 	}
-	return false
+	em.mu.Unlock()
+
+	em.Lifecycle.despawned(removed...)
+	return len(removed)
+}
+
+// RemoveWhere removes every entity matching the predicate and returns how many were removed (thread-safe).
+// Remaining entities keep their relative order. OnDespawn fires for each in manager order.
+func (em *EntityManager) RemoveWhere(match func(Entity) bool) int {
+	em.mu.Lock()
+	var removed []Entity
+	kept := em.entities[:0]
+	for _, entity := range em.entities {
+		if match(entity) {
+			delete(em.index, entity.GetID())
+			removed = append(removed, entity)
+			continue
+		}
+		em.index[entity.GetID()] = len(kept)
+		kept = append(kept, entity)
+	}
+	clear(em.entities[len(kept):]) // Drop references so removed entities can be collected
+	em.entities = kept
+	if len(removed) > 0 {
+		em.spatial = nil
+	}
+	em.mu.Unlock()
+
+	em.Lifecycle.despawned(removed...)
+	return len(removed)
+}
+
+// removeLocked swaps the entity with the last one and truncates; the caller holds the lock
+func (em *EntityManager) removeLocked(id string) (Entity, bool) {
+	i, ok := em.index[id]
+	if !ok {
+		return nil, false
+	}
+	entity := em.entities[i]
+	last := len(em.entities) - 1
+	if i != last {
+		em.entities[i] = em.entities[last]
+		em.index[em.entities[i].GetID()] = i
+	}
+	em.entities[last] = nil
+	em.entities = em.entities[:last]
+	delete(em.index, id)
+	em.spatial = nil
+	return entity, true
+}
+
+// Each calls fn for every entity. It iterates over a snapshot, so fn may add
+// or remove entities (including the current one) without affecting the loop.
+func (em *EntityManager) Each(fn func(Entity)) {
+	for _, entity := range em.GetEntities() {
+		fn(entity)
+	}
 }
 
 // GetEntities returns a copy of all entities to prevent concurrent modification issues (thread-safe)
@@ -430,8 +702,10 @@ func (em *EntityManager) GetEntities() []Entity {
 	return entities
 }
 
-// GetEntitiesByType returns entities of a specific type
+// GetEntitiesByType returns entities of a specific type (thread-safe)
 func (em *EntityManager) GetEntitiesByType(entityType EntityType) []Entity {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
 	var result []Entity
 	for _, entity := range em.entities {
 		if entity.GetType() == entityType {
@@ -441,11 +715,17 @@ func (em *EntityManager) GetEntitiesByType(entityType EntityType) []Entity {
 	return result
 }
 
-// Clear removes all entities (thread-safe)
+// Clear removes all entities (thread-safe). The ID counter keeps counting.
+// OnDespawn fires for each entity in manager order.
 func (em *EntityManager) Clear() {
 	em.mu.Lock()
-	defer em.mu.Unlock()
+	removed := em.entities
 	em.entities = make([]Entity, 0)
+	em.index = make(map[string]int)
+	em.spatial = nil
+	em.mu.Unlock()
+
+	em.Lifecycle.despawned(removed...)
 }
 
 // Count returns the number of entities (thread-safe)
@@ -504,9 +784,30 @@ type CollisionPair struct {
 
 // Utility functions
 
-// generateID generates a unique ID for entities
+// effectiveSizeFor maps an entity size to the collision diameter that matches
+// its single-character visual representation
+func effectiveSizeFor(size int) float64 {
+	switch size {
+	case 1:
+		return 0.8 // Tiny
+	case 2:
+		return 1.0 // Small
+	case 3:
+		return 1.3 // Medium
+	case 4:
+		return 1.6 // Large
+	default:
+		return float64(size) * 0.8
+	}
+}
+
+// unmanagedIDs numbers entities that have not been added to an EntityManager yet
+var unmanagedIDs atomic.Uint64
+
+// generateID generates a unique provisional ID for a new entity.
+// EntityManager.AddEntity replaces it with a monotonic managed ID.
 func generateID(prefix string) string {
-	return fmt.Sprintf("%s_%d_%d", prefix, rand.Intn(10000), rand.Intn(10000))
+	return fmt.Sprintf("%s_new_%d", prefix, unmanagedIDs.Add(1))
 }
 
 // GetRandomColor returns a random color for entities using reliable hex colors
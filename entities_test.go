@@ -163,6 +163,104 @@ func TestEntityManagerRemoveEntity(t *testing.T) {
 	}
 }
 
+func TestEntityManagerMonotonicIDs(t *testing.T) {
+	manager := NewEntityManager()
+	sphere := NewSphere(5.0, 5.0, 1, lipgloss.Color("32"))
+	sprite := NewSprite(10.0, 10.0, 1, lipgloss.Color("34"), "★")
+
+	manager.AddEntity(sphere)
+	manager.AddEntity(sprite)
+	if sphere.GetID() != "sphere_1" || sprite.GetID() != "sprite_2" {
+		t.Errorf("Expected sequential IDs, got %s and %s", sphere.GetID(), sprite.GetID())
+	}
+
+	// Adding the same entity again is a no-op
+	manager.AddEntity(sphere)
+	if manager.Count() != 2 || sphere.GetID() != "sphere_1" {
+		t.Error("Expected re-adding an entity to leave it unchanged")
+	}
+
+	// IDs are not reused after Clear
+	manager.Clear()
+	boid := NewBoid(5.0, 5.0, 1, lipgloss.Color("32"))
+	manager.AddEntity(boid)
+	if boid.GetID() != "boid_3" {
+		t.Errorf("Expected IDs to keep counting after Clear, got %s", boid.GetID())
+	}
+	if _, ok := manager.Get("sphere_1"); ok {
+		t.Error("Expected cleared entities to be gone from the index")
+	}
+}
+
+func TestEntityManagerGet(t *testing.T) {
+	manager := NewEntityManager()
+	var spheres []*Sphere
+	for i := 0; i < 5; i++ {
+		sphere := NewSphere(float64(i), 5.0, 1, lipgloss.Color("32"))
+		spheres = append(spheres, sphere)
+		manager.AddEntity(sphere)
+	}
+
+	// Removing from the middle moves the last entity; the index must follow it
+	manager.RemoveEntity(spheres[1].GetID())
+	for i, sphere := range spheres {
+		entity, ok := manager.Get(sphere.GetID())
+		if i == 1 {
+			if ok {
+				t.Error("Expected removed entity not to be found")
+			}
+			continue
+		}
+		if !ok || entity != Entity(sphere) {
+			t.Errorf("Expected Get to find sphere %d", i)
+		}
+	}
+}
+
+func TestEntityManagerBulkRemove(t *testing.T) {
+	manager := NewEntityManager()
+	var ids []string
+	for i := 0; i < 6; i++ {
+		sphere := NewSphere(float64(i), 5.0, 1, lipgloss.Color("32"))
+		manager.AddEntity(sphere)
+		ids = append(ids, sphere.GetID())
+	}
+
+	if removed := manager.RemoveEntities(ids[0], ids[2], "missing"); removed != 2 {
+		t.Errorf("Expected 2 entities removed, got %d", removed)
+	}
+
+	removed := manager.RemoveWhere(func(e Entity) bool {
+		x, _ := e.GetPosition()
+		return x >= 4
+	})
+	if removed != 2 || manager.Count() != 2 {
+		t.Errorf("Expected RemoveWhere to remove 2 and keep 2, removed %d kept %d", removed, manager.Count())
+	}
+	for _, id := range []string{ids[1], ids[3]} {
+		if _, ok := manager.Get(id); !ok {
+			t.Errorf("Expected %s to remain indexed", id)
+		}
+	}
+}
+
+func TestEntityManagerRemoveDuringIteration(t *testing.T) {
+	manager := NewEntityManager()
+	for i := 0; i < 4; i++ {
+		manager.AddEntity(NewSphere(float64(i), 5.0, 1, lipgloss.Color("32")))
+	}
+
+	visited := 0
+	manager.Each(func(e Entity) {
+		visited++
+		manager.RemoveEntity(e.GetID())
+	})
+
+	if visited != 4 || manager.Count() != 0 {
+		t.Errorf("Expected to visit and remove all 4 entities, visited %d with %d left", visited, manager.Count())
+	}
+}
+
 func TestEntityManagerGetEntitiesByType(t *testing.T) {
 	manager := NewEntityManager()
 
@@ -324,13 +422,13 @@ func TestEntityRendering(t *testing.T) {
 
 	// Test sphere rendering
 	sphereRender := sphere.Render()
-	if sphereRender == "" {
+	if sphereRender.Empty() {
 		t.Error("Expected non-empty sphere render")
 	}
 
 	// Test sprite rendering
 	spriteRender := sprite.Render()
-	if spriteRender == "" {
+	if spriteRender.Empty() {
 		t.Error("Expected non-empty sprite render")
 	}
 
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BodyMaterial describes what an entity is made of
+type BodyMaterial struct {
+	Name    string
+	Density float64 // Mass per unit of effective size
+}
+
+// Body materials used by the built-in entity types
+var (
+	RubberMaterial  = BodyMaterial{Name: "rubber", Density: 1.0}
+	PlasticMaterial = BodyMaterial{Name: "plastic", Density: 0.8}
+	FeatherMaterial = BodyMaterial{Name: "feather", Density: 0.5}
+)
+
+// EntityTypeInfo describes an entity kind to the rest of the simulation.
+// The control panel buttons, spawn keys and status breakdown are generated from it.
+type EntityTypeInfo struct {
+	Type   EntityType
+	Name   string // Display name, e.g. "Sphere"
+	Plural string // Used in the status breakdown, e.g. "spheres"
+	Icon   string // Single glyph for compact buttons and the status breakdown
+	Key    string // Key that spawns the type; empty for none
+
+	// Glyphs by size, starting at size 1. Sizes beyond the list use the entity's Symbol.
+	// Multi-cell sizes still use their glyph where only one cell can be drawn.
+	Glyphs []string
+
+	// Outline that multi-cell entities of this type draw and collide with
+	Shape ShapeKind
+
+	// Material new entities of this type are made of
+	Material BodyMaterial
+
+	// New creates an entity of this type
+	New func(x, y float64, size int, color lipgloss.Color) Entity
+
+	// Order sorts types in the control panel and status line
+	Order int
+}
+
+// entityTypeRegistry holds every registered entity type
+var entityTypeRegistry = map[EntityType]EntityTypeInfo{}
+
+// reservedKeys are bound by Model.Update, the control panel, the inspector and
+// handleCameraKey. Spawn keys are looked up before most of them, so a type on one
+// of these keys would shadow it.
+var reservedKeys = map[string]bool{
+	"q": true, "ctrl+c": true, "c": true, "p": true, "r": true, "g": true, "e": true,
+	"w": true, "b": true, "z": true, "x": true, "v": true, "h": true, "k": true,
+	"j": true, "K": true, "m": true, "n": true, "u": true, "U": true, "F": true,
+	"d": true, "O": true, "R": true, "i": true, "y": true, "Y": true, "f": true,
+	"t": true, "l": true,
+	"+": true, "=": true, "-": true, "@": true, "0": true,
+	"tab": true, "shift+tab": true, "enter": true, " ": true, "esc": true, "backspace": true,
+	"up": true, "down": true, "left": true, "right": true,
+	"shift+up": true, "shift+down": true, "shift+left": true, "shift+right": true,
+	"ctrl+up": true, "ctrl+down": true, "ctrl+left": true, "ctrl+right": true,
+}
+
+// checkEntityType reports why a type cannot be registered: its name is taken, or
+// its spawn key is bound by the simulation or another type
+func checkEntityType(info EntityTypeInfo) error {
+	if _, ok := entityTypeRegistry[info.Type]; ok {
+		return fmt.Errorf("entity type %q is already registered", info.Type)
+	}
+	if info.Key == "" {
+		return nil
+	}
+	if reservedKeys[info.Key] {
+		return fmt.Errorf("entity type %q: key %q is already bound", info.Type, info.Key)
+	}
+	if other, ok := entityTypeForKey(info.Key); ok {
+		return fmt.Errorf("entity type %q: key %q already spawns %s", info.Type, info.Key, other.Type)
+	}
+	return nil
+}
+
+// RegisterEntityType makes an entity kind available to the simulation.
+// Types register themselves from an init function in their own file.
+func RegisterEntityType(info EntityTypeInfo) error {
+	if err := checkEntityType(info); err != nil {
+		return err
+	}
+	if info.Plural == "" {
+		info.Plural = strings.ToLower(info.Name) + "s"
+	}
+	entityTypeRegistry[info.Type] = info
+	return nil
+}
+
+// mustRegisterEntityType registers a built-in type, panicking if it clashes
+func mustRegisterEntityType(info EntityTypeInfo) {
+	if err := RegisterEntityType(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupEntityType returns the registration for an entity type
+func LookupEntityType(entityType EntityType) (EntityTypeInfo, bool) {
+	info, ok := entityTypeRegistry[entityType]
+	return info, ok
+}
+
+// RegisteredEntityTypes returns every registered type in display order
+func RegisteredEntityTypes() []EntityTypeInfo {
+	types := make([]EntityTypeInfo, 0, len(entityTypeRegistry))
+	for _, info := range entityTypeRegistry {
+		types = append(types, info)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Order != types[j].Order {
+			return types[i].Order < types[j].Order
+		}
+		return types[i].Type < types[j].Type
+	})
+	return types
+}
+
+// entityTypeForKey returns the type spawned by a key press
+func entityTypeForKey(key string) (EntityTypeInfo, bool) {
+	for _, info := range RegisteredEntityTypes() {
+		if info.Key != "" && info.Key == key {
+			return info, true
+		}
+	}
+	return EntityTypeInfo{}, false
+}
+
+// NewEntityOfType creates an entity of a registered type
+func NewEntityOfType(entityType EntityType, x, y float64, size int, color lipgloss.Color) (Entity, bool) {
+	info, ok := LookupEntityType(entityType)
+	if !ok || info.New == nil {
+		return nil, false
+	}
+	return info.New(x, y, size, color), true
+}
+
+// glyphFor returns the registered glyph for an entity type at a size
+func glyphFor(entityType EntityType, size int) (string, bool) {
+	info, ok := LookupEntityType(entityType)
+	if !ok || size < 1 || size > len(info.Glyphs) {
+		return "", false
+	}
+	return info.Glyphs[size-1], true
+}
+
+// massFor returns the mass of an entity of a registered type and size
+func massFor(entityType EntityType, size int) float64 {
+	density := 1.0
+	if info, ok := LookupEntityType(entityType); ok && info.Material.Density > 0 {
+		density = info.Material.Density
+	}
+	return effectiveSizeFor(size) * density
+}
+
+// addActionFor returns the control panel action that spawns an entity type
+func addActionFor(entityType EntityType) ButtonAction {
+	return ButtonAction("add_" + string(entityType))
+}
+
+// entityTypeForAction returns the entity type spawned by a control panel action
+func entityTypeForAction(action ButtonAction) (EntityType, bool) {
+	name, ok := strings.CutPrefix(string(action), "add_")
+	if !ok {
+		return "", false
+	}
+	if _, registered := LookupEntityType(EntityType(name)); !registered {
+		return "", false
+	}
+	return EntityType(name), true
+}
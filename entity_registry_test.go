@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// registerTestCrate registers a throwaway entity type for the duration of a test
+func registerTestCrate(t *testing.T) EntityType {
+	const crateType EntityType = "crate"
+	err := RegisterEntityType(EntityTypeInfo{
+		Type:     crateType,
+		Name:     "Crate",
+		Icon:     "▣",
+		Key:      "9",
+		Glyphs:   []string{"▫", "▣"},
+		Material: BodyMaterial{Name: "wood", Density: 2.0},
+		New: func(x, y float64, size int, color lipgloss.Color) Entity {
+			sphere := NewSphere(x, y, size, color)
+			sphere.Type = crateType
+			sphere.Mass = massFor(crateType, size)
+			return sphere
+		},
+		Order: 40,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected register error: %v", err)
+	}
+	t.Cleanup(func() { delete(entityTypeRegistry, crateType) })
+	return crateType
+}
+
+func TestBuiltinEntityTypesRegistered(t *testing.T) {
+	types := RegisteredEntityTypes()
+	if len(types) < 3 || types[0].Type != SphereType || types[1].Type != SpriteType || types[2].Type != BoidType {
+		t.Fatalf("Expected spheres, sprites and boids in display order, got %v", types)
+	}
+	if types[0].Plural != "spheres" {
+		t.Errorf("Expected plural to default from the name, got %q", types[0].Plural)
+	}
+
+	for size, glyph := range []string{"●", "⬤", "⭘", "⬢"} {
+		sphere := NewSphere(5, 5, size+1, lipgloss.Color("32"))
+		if rendered := sphere.Render().Glyph; rendered != glyph {
+			t.Errorf("Expected size %d sphere to render %s, got %s", size+1, glyph, rendered)
+		}
+	}
+
+	if NewSprite(5, 5, 1, lipgloss.Color("32"), "★").Mass >= NewSphere(5, 5, 1, lipgloss.Color("32")).Mass {
+		t.Error("Expected the plastic sprite to be lighter than the rubber sphere")
+	}
+}
+
+func TestRegisteredTypeDrivesUI(t *testing.T) {
+	crateType := registerTestCrate(t)
+
+	entity, ok := NewEntityOfType(crateType, 5, 5, 2, lipgloss.Color("32"))
+	if !ok || entity.GetType() != crateType {
+		t.Fatal("Expected the registry to construct the new type")
+	}
+	if rendered := entity.Render().Glyph; rendered != "▣" {
+		t.Errorf("Expected the registered glyph, got %s", rendered)
+	}
+
+	// The control panel gets an add button for the new type
+	cp := NewControlPanel(120, 20)
+	found := false
+	for _, button := range cp.buttons {
+		if button.Label == "Add Crate" && button.Action == addActionFor(crateType) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected an Add Crate button")
+	}
+
+	// Its key and button spawn it, and the status breakdown counts it
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 240, Height: 30})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(ButtonMsg{Action: addActionFor(crateType)})
+	model = updatedModel.(Model)
+
+	if model.entityManager.CountByType(crateType) != 2 {
+		t.Fatalf("Expected 2 crates, got %d", model.entityManager.CountByType(crateType))
+	}
+	model.renderer = NewCellBuffer()
+	view := model.renderSimulation()
+	if !strings.Contains(view, "● 0 spheres | ◆ 0 sprites | ➤ 0 boids | ▣ 2 crates") {
+		t.Error("Expected the status breakdown to be generated from the registry")
+	}
+}
+
+func TestEntityTypeForAction(t *testing.T) {
+	if entityType, ok := entityTypeForAction(AddSphereAction); !ok || entityType != SphereType {
+		t.Error("Expected AddSphereAction to map to spheres")
+	}
+	if _, ok := entityTypeForAction(ClearAllAction); ok {
+		t.Error("Expected non-add actions to map to no type")
+	}
+	if _, ok := entityTypeForAction("add_unknown"); ok {
+		t.Error("Expected unregistered types to be rejected")
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderMode selects how entities are drawn into the simulation pane's character cells
+type RenderMode int
+
+const (
+	RenderGlyphs     RenderMode = iota // One glyph per cell
+	RenderHalfBlocks                   // ▀▄ with separate top and bottom colors: 1×2 dots per cell
+	RenderBraille                      // ⣿ patterns: 2×4 dots per cell
+)
+
+// renderModeNames are shown in the status line and accepted by the -render flag
+var renderModeNames = []string{"glyphs", "halfblocks", "braille"}
+
+// String returns the mode's name
+func (mode RenderMode) String() string {
+	return renderModeNames[mode]
+}
+
+// Resolution returns the dots each character cell holds across and down. In the
+// high-resolution modes one world cell is one dot.
+func (mode RenderMode) Resolution() (x, y int) {
+	switch mode {
+	case RenderHalfBlocks:
+		return 1, 2
+	case RenderBraille:
+		return 2, 4
+	}
+	return 1, 1
+}
+
+// parseRenderMode parses a render mode name
+func parseRenderMode(name string) (RenderMode, error) {
+	for i, candidate := range renderModeNames {
+		if strings.EqualFold(name, candidate) {
+			return RenderMode(i), nil
+		}
+	}
+	return RenderGlyphs, fmt.Errorf("render mode %q must be one of %s", name, strings.Join(renderModeNames, ", "))
+}
+
+// brailleBits are the Unicode braille dot bits, indexed [row][column] within a cell
+var brailleBits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// DotCanvas collects colored dots for a grid of character cells and turns each
+// cell's dots into one braille or half-block glyph
+type DotCanvas struct {
+	Width, Height int // In character cells
+	mode          RenderMode
+	resX, resY    int
+	dots          []lipgloss.Color // Row-major over every dot; "" is unset
+}
+
+// NewDotCanvas creates an empty canvas of width × height cells for a render mode
+func NewDotCanvas(width, height int, mode RenderMode) *DotCanvas {
+	resX, resY := mode.Resolution()
+	return &DotCanvas{
+		Width: width, Height: height,
+		mode: mode, resX: resX, resY: resY,
+		dots: make([]lipgloss.Color, width*resX*height*resY),
+	}
+}
+
+// Set colors the dot at (x, y), counted in dots from the top-left; later dots win
+func (c *DotCanvas) Set(x, y int, color lipgloss.Color) {
+	dotsWide := c.Width * c.resX
+	if x < 0 || y < 0 || x >= dotsWide || y >= c.Height*c.resY {
+		return
+	}
+	c.dots[y*dotsWide+x] = color
+}
+
+// dot returns the color of a dot within cell (sx, sy)
+func (c *DotCanvas) dot(sx, sy, dx, dy int) lipgloss.Color {
+	return c.dots[(sy*c.resY+dy)*c.Width*c.resX+sx*c.resX+dx]
+}
+
+// Cell returns the glyph for cell (sx, sy), or an empty cell when none of its dots are set
+func (c *DotCanvas) Cell(sx, sy int) Cell {
+	if sx < 0 || sy < 0 || sx >= c.Width || sy >= c.Height {
+		return Cell{}
+	}
+	switch c.mode {
+	case RenderHalfBlocks:
+		top, bottom := c.dot(sx, sy, 0, 0), c.dot(sx, sy, 0, 1)
+		switch {
+		case top != "" && bottom != "":
+			return Cell{Glyph: "▀", Color: top, Background: bottom}
+		case top != "":
+			return Cell{Glyph: "▀", Color: top}
+		case bottom != "":
+			return Cell{Glyph: "▄", Color: bottom}
+		}
+	case RenderBraille:
+		// A braille cell has one color; the last dot drawn in it, scanning down, sets it
+		var pattern rune
+		var color lipgloss.Color
+		for dy := 0; dy < c.resY; dy++ {
+			for dx := 0; dx < c.resX; dx++ {
+				if dot := c.dot(sx, sy, dx, dy); dot != "" {
+					pattern |= brailleBits[dy][dx]
+					color = dot
+				}
+			}
+		}
+		if pattern != 0 {
+			return Cell{Glyph: string(0x2800 + pattern), Color: color}
+		}
+	}
+	return Cell{}
+}
+
+// drawEntityDots draws every entity's footprint as dots, bottom to top by z-order so
+// higher entities' dots win, and composes the dots into grid cells. Each dot has one
+// color, so the overlap policy does not apply. Flashing entities are drawn in their
+// flash color, and the cell at the selected entity's center is highlighted.
+func drawEntityDots(grid [][]Cell, cam Camera, mode RenderMode, entities []Entity, selectedID string) {
+	if len(grid) == 0 {
+		return
+	}
+	canvas := NewDotCanvas(len(grid[0]), len(grid), mode)
+	for _, entity := range sortByZOrder(entities) {
+		color := entity.GetColor()
+		if state := entity.GetAnimationState(); state != nil && state.Flash > 0 {
+			color = blendColors(color, lipgloss.Color("#FFFFFF"), 0.6)
+		}
+		forEachDot(cam, entity, func(dx, dy int) {
+			canvas.Set(dx, dy, color)
+		})
+	}
+
+	for sy := range grid {
+		for sx := range grid[sy] {
+			if cell := canvas.Cell(sx, sy); !cell.Empty() {
+				grid[sy][sx] = cell
+			}
+		}
+	}
+
+	for _, entity := range entities {
+		if selectedID == "" || entity.GetID() != selectedID {
+			continue
+		}
+		sx, sy := cam.ToScreen(entity.GetDisplayPosition())
+		if sy >= 0 && sy < len(grid) && sx >= 0 && sx < len(grid[sy]) && !grid[sy][sx].Empty() {
+			grid[sy][sx] = highlightCell(grid[sy][sx], entity.GetColor())
+		}
+	}
+}
+
+// forEachDot calls draw for every view dot of an entity's footprint around its
+// display position, at least one dot
+func forEachDot(cam Camera, entity Entity, draw func(dx, dy int)) {
+	zoom := float64(cam.zoom())
+	x, y := entity.GetDisplayPosition()
+	_, _, width, height := entity.GetBounds()
+	left, top := cam.ToDot(x-width/2, y-height/2)
+	dotsWide := max(1, int(width/zoom+0.5))
+	dotsHigh := max(1, int(height/zoom+0.5))
+	for dy := 0; dy < dotsHigh; dy++ {
+		for dx := 0; dx < dotsWide; dx++ {
+			draw(left+dx, top+dy)
+		}
+	}
+}
+
+// pickDot returns the topmost entity with a dot in view cell (sx, sy), the one
+// drawEntityDots shows there, for mouse picking
+func pickDot(cam Camera, mode RenderMode, entities []Entity, sx, sy int) (Entity, bool) {
+	resX, resY := mode.Resolution()
+	sorted := sortByZOrder(entities)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		drawn := false
+		forEachDot(cam, sorted[i], func(dx, dy int) {
+			if dx >= 0 && dy >= 0 && dx/resX == sx && dy/resY == sy {
+				drawn = true
+			}
+		})
+		if drawn {
+			return sorted[i], true
+		}
+	}
+	return nil, false
+}
+
+// setRenderMode switches render modes. Unless the world has a fixed size it follows
+// the pane, so the world is rescaled to the new resolution and everything in it is
+// moved to stay where it appears on screen.
+func (m *Model) setRenderMode(mode RenderMode) {
+	oldX, oldY := m.renderMode.Resolution()
+	m.renderMode = mode
+	m.camera.ResX, m.camera.ResY = mode.Resolution()
+	newX, newY := mode.Resolution()
+
+	if m.worldWidth == 0 || m.worldHeight == 0 {
+		scaleX, scaleY := float64(newX)/float64(oldX), float64(newY)/float64(oldY)
+		for _, entity := range m.entityManager.GetEntities() {
+			x, y := entity.GetPosition()
+			vx, vy := entity.GetVelocity()
+			entity.SetPosition(x*scaleX, y*scaleY)
+			entity.SetVelocity(vx*scaleX, vy*scaleY)
+			if state := entity.GetAnimationState(); state != nil {
+				state.DisplayX, state.DisplayY = state.DisplayX*scaleX, state.DisplayY*scaleY
+				state.Trail = nil
+			}
+		}
+		for _, sensor := range m.sensors.Sensors() {
+			sensor.X, sensor.Y = sensor.X*scaleX, sensor.Y*scaleY
+			sensor.Width, sensor.Height = sensor.Width*scaleX, sensor.Height*scaleY
+			sensor.OnEnter.TeleportX, sensor.OnEnter.TeleportY = sensor.OnEnter.TeleportX*scaleX, sensor.OnEnter.TeleportY*scaleY
+		}
+		m.rescaleMaterials(oldX, oldY, newX, newY)
+		m.brushX, m.brushY = m.brushX*newX/oldX, m.brushY*newY/oldY
+
+		worldWidth, worldHeight := m.worldSize()
+		m.physicsEngine.UpdateBounds(float64(worldWidth), float64(worldHeight))
+		m.handleBoundaryResize(float64(worldWidth), float64(worldHeight))
+	}
+	m.clampCamera()
+}
+
+// rescaleMaterials resizes the material layer to the world and stretches or shrinks
+// what was painted so it covers the same part of the pane
+func (m *Model) rescaleMaterials(oldX, oldY, newX, newY int) {
+	old := m.materials
+	width, height := m.materialGridSize()
+	scaled := NewMaterialGrid(width, height)
+	scaled.DownX, scaled.DownY = old.DownX, old.DownY
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if material := old.Get(x*oldX/newX, y*oldY/newY); material != MaterialEmpty {
+				scaled.Set(x, y, material)
+			}
+		}
+	}
+	*m.materials = *scaled
+}
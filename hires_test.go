@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDotCanvasGlyphs(t *testing.T) {
+	red, blue := lipgloss.Color("#FF0000"), lipgloss.Color("#0000FF")
+
+	braille := NewDotCanvas(2, 1, RenderBraille)
+	braille.Set(0, 0, red)
+	braille.Set(1, 3, red)
+	if cell := braille.Cell(0, 0); cell != (Cell{Glyph: "⢁", Color: red}) {
+		t.Errorf("Expected the top-left and bottom-right dots as ⢁, got %+v", cell)
+	}
+	if !braille.Cell(1, 0).Empty() {
+		t.Error("Expected a cell with no dots to be empty")
+	}
+	braille.Set(9, 9, red) // Off the canvas: ignored
+
+	halves := NewDotCanvas(1, 2, RenderHalfBlocks)
+	halves.Set(0, 0, red)
+	halves.Set(0, 1, blue)
+	halves.Set(0, 3, blue)
+	if cell := halves.Cell(0, 0); cell != (Cell{Glyph: "▀", Color: red, Background: blue}) {
+		t.Errorf("Expected a two-color half block, got %+v", cell)
+	}
+	if cell := halves.Cell(0, 1); cell != (Cell{Glyph: "▄", Color: blue}) {
+		t.Errorf("Expected a lower half block, got %+v", cell)
+	}
+
+	if mode, err := parseRenderMode("Braille"); err != nil || mode != RenderBraille {
+		t.Errorf("Expected braille to parse, got %s, %v", mode, err)
+	}
+	if _, err := parseRenderMode("ascii"); err == nil {
+		t.Error("Expected an unknown render mode to be rejected")
+	}
+}
+
+func TestRenderModeScalesWorld(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	glyphWidth, glyphHeight := model.worldSize()
+
+	sphere := NewSphere(10.5, 5.5, 1, lipgloss.Color("#FF0000"))
+	sphere.SetFrozen(true)
+	model.entityManager.AddEntity(sphere)
+	model.materials.Set(4, 6, MaterialStone)
+	portal := newPresetSensor(2, 30, 2, 6, 4, 40.5, 5.5)
+	model.sensors.Add(portal)
+	screenX, screenY := model.camera.ToScreen(sphere.GetDisplayPosition())
+
+	press := func() {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+		model = updatedModel.(Model)
+	}
+
+	press()
+	press()
+	if model.renderMode != RenderBraille {
+		t.Fatalf("Expected R twice to reach braille, got %s", model.renderMode)
+	}
+	width, height := model.worldSize()
+	if width != glyphWidth*2 || height != glyphHeight*4 {
+		t.Errorf("Expected the world scaled 2×4 to %dx%d, got %dx%d", glyphWidth*2, glyphHeight*4, width, height)
+	}
+	if _, materialHeight := model.materialGridSize(); model.physicsEngine.MaxX != float64(width)-2 || model.materials.Height != materialHeight {
+		t.Error("Expected the physics bounds and material layer to follow the world")
+	}
+	if x, y := model.camera.ToScreen(sphere.GetDisplayPosition()); x != screenX || y != screenY {
+		t.Errorf("Expected the sphere to stay on screen cell (%d, %d), got (%d, %d)", screenX, screenY, x, y)
+	}
+	if model.materials.Get(8, 24) != MaterialStone || model.materials.Get(9, 27) != MaterialStone {
+		t.Error("Expected painted materials to cover the same cell after rescaling")
+	}
+	if portal.X != 60 || portal.Y != 8 || portal.Width != 12 || portal.Height != 16 {
+		t.Errorf("Expected the zone to cover the same cells after rescaling, got %+v", *portal)
+	}
+	if portal.OnEnter.TeleportX != 81 || portal.OnEnter.TeleportY != 22 {
+		t.Errorf("Expected the portal's exit to move with the world, got (%.1f, %.1f)", portal.OnEnter.TeleportX, portal.OnEnter.TeleportY)
+	}
+
+	// A size-1 sphere is one dot of a braille cell
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	model.renderSimulation()
+	if glyph := buffer.Glyph(screenX, screenY); !strings.Contains("⠁⠂⠄⡀⠈⠐⠠⢀", glyph) || glyph == "" {
+		t.Errorf("Expected the sphere drawn as a single braille dot, got %q", glyph)
+	}
+	if !strings.Contains(model.renderSimulation(), "Render: braille") {
+		t.Error("Expected the status line to show the render mode")
+	}
+
+	press()
+	if width, height := model.worldSize(); width != glyphWidth || height != glyphHeight {
+		t.Errorf("Expected glyph mode to restore the world size, got %dx%d", width, height)
+	}
+	if x, y := sphere.GetPosition(); x != 10.5 || y != 5.5 {
+		t.Errorf("Expected the sphere back where it started, got (%.2f, %.2f)", x, y)
+	}
+	if portal.X != 30 || portal.Width != 6 || portal.OnEnter.TeleportX != 40.5 || portal.OnEnter.TeleportY != 5.5 {
+		t.Errorf("Expected the zone and its exit back where they started, got %+v", *portal)
+	}
+}
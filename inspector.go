@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Inspector shows and edits the fields of one selected entity.
+// While it is open it takes the arrow keys, enter and esc, and clicks in the
+// simulation select the entity under the cursor.
+type Inspector struct {
+	Open       bool
+	SelectedID string
+	Field      int // Index into inspectorFields
+
+	// In-place editing of the current field
+	Editing bool
+	Input   string
+	Error   string // Why the last edit was rejected
+}
+
+// inspectorField is one row of the inspector. Read-only rows have no Set.
+type inspectorField struct {
+	Label string
+	Get   func(e Entity) string
+	Set   func(e Entity, value string) error
+	Step  float64 // Amount an arrow-key nudge changes the value by; 0 for no nudging
+}
+
+// inspectorFields lists the rows of the inspector in display order
+var inspectorFields = []inspectorField{
+	{Label: "ID", Get: func(e Entity) string { return e.GetID() }},
+	{Label: "Type", Get: func(e Entity) string { return string(e.GetType()) }},
+	{
+		Label: "Size",
+		Get:   func(e Entity) string { return strconv.Itoa(e.GetSize()) },
+		Set: func(e Entity, value string) error {
+			// Sizes past the ones the UI offers would draw enormous shapes every frame
+			largest := entitySizes[len(entitySizes)-1]
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 1 || size > largest {
+				return fmt.Errorf("size must be a whole number from 1 to %d", largest)
+			}
+			resizable, ok := e.(interface{ SetSize(int) })
+			if !ok {
+				return fmt.Errorf("%s cannot be resized", e.GetType())
+			}
+			resizable.SetSize(size)
+			return nil
+		},
+		Step: 1,
+	},
+	{
+		Label: "Mass",
+		Get: func(e Entity) string {
+			if massive, ok := e.(interface{ GetMass() float64 }); ok {
+				return strconv.FormatFloat(massive.GetMass(), 'f', 2, 64)
+			}
+			return "-"
+		},
+		Set: func(e Entity, value string) error {
+			mass, err := parseInspectorFloat(value)
+			if err != nil {
+				return err
+			}
+			if mass <= 0 {
+				return fmt.Errorf("mass must be a positive number")
+			}
+			massive, ok := e.(interface{ SetMass(float64) })
+			if !ok {
+				return fmt.Errorf("%s has no mass", e.GetType())
+			}
+			massive.SetMass(mass)
+			return nil
+		},
+		Step: 0.1,
+	},
+	{
+		Label: "Z",
+		Get:   func(e Entity) string { return strconv.Itoa(zOrderOf(e)) },
+		Set: func(e Entity, value string) error {
+			z, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("z-order must be a whole number")
+			}
+			ordered, ok := e.(interface{ SetZOrder(int) })
+			if !ok {
+				return fmt.Errorf("%s has no z-order", e.GetType())
+			}
+			ordered.SetZOrder(z)
+			return nil
+		},
+		Step: 1,
+	},
+	{
+		Label: "X",
+		Get:   func(e Entity) string { x, _ := e.GetPosition(); return formatInspectorFloat(x) },
+		Set: func(e Entity, value string) error {
+			return setInspectorFloat(value, func(v float64) { _, y := e.GetPosition(); teleport(e, v, y) })
+		},
+		Step: 1,
+	},
+	{
+		Label: "Y",
+		Get:   func(e Entity) string { _, y := e.GetPosition(); return formatInspectorFloat(y) },
+		Set: func(e Entity, value string) error {
+			return setInspectorFloat(value, func(v float64) { x, _ := e.GetPosition(); teleport(e, x, v) })
+		},
+		Step: 1,
+	},
+	{
+		Label: "VX",
+		Get:   func(e Entity) string { vx, _ := e.GetVelocity(); return formatInspectorFloat(vx) },
+		Set: func(e Entity, value string) error {
+			return setInspectorFloat(value, func(v float64) { _, vy := e.GetVelocity(); e.SetVelocity(v, vy) })
+		},
+		Step: 1,
+	},
+	{
+		Label: "VY",
+		Get:   func(e Entity) string { _, vy := e.GetVelocity(); return formatInspectorFloat(vy) },
+		Set: func(e Entity, value string) error {
+			return setInspectorFloat(value, func(v float64) { vx, _ := e.GetVelocity(); e.SetVelocity(vx, v) })
+		},
+		Step: 1,
+	},
+	{
+		Label: "Display",
+		Get: func(e Entity) string {
+			x, y := e.GetDisplayPosition()
+			return formatInspectorFloat(x) + ", " + formatInspectorFloat(y)
+		},
+	},
+	{
+		Label: "Animation",
+		Get: func(e Entity) string {
+			state := e.GetAnimationState()
+			if state == nil {
+				return "none"
+			}
+			status := "settled"
+			if state.IsStillAnimating() {
+				status = "animating"
+			}
+			return fmt.Sprintf("%s, spring v %s, %s", status,
+				formatInspectorFloat(state.VelocityX), formatInspectorFloat(state.VelocityY))
+		},
+	},
+	{
+		Label: "Color",
+		Get:   func(e Entity) string { return string(e.GetColor()) },
+		Set: func(e Entity, value string) error {
+			recolorable, ok := e.(interface{ SetColor(lipgloss.Color) })
+			if !ok || value == "" {
+				return fmt.Errorf("color cannot be set")
+			}
+			recolorable.SetColor(lipgloss.Color(value))
+			return nil
+		},
+	},
+}
+
+// formatInspectorFloat formats a number for an inspector row
+func formatInspectorFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// setInspectorFloat parses a typed number and applies it
+func setInspectorFloat(value string, apply func(float64)) error {
+	v, err := parseInspectorFloat(value)
+	if err != nil {
+		return err
+	}
+	apply(v)
+	return nil
+}
+
+// parseInspectorFloat parses a typed number, rejecting NaN and infinities
+func parseInspectorFloat(value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return v, nil
+}
+
+// setInspectorField applies an edit to the selected entity, then keeps the entity
+// inside the physics bounds and under the velocity cap, so typed positions and
+// speeds behave like ones the simulation produced
+func (m *Model) setInspectorField(entity Entity, field inspectorField, value string) error {
+	if err := field.Set(entity, value); err != nil {
+		return err
+	}
+	pe := m.physicsEngine
+	halfWidth, halfHeight := boundaryExtents(entity)
+	x, y := entity.GetPosition()
+	clampedX := math.Max(pe.MinX+halfWidth, math.Min(x, pe.MaxX-halfWidth))
+	clampedY := math.Max(pe.MinY+halfHeight, math.Min(y, pe.MaxY-halfHeight))
+	if clampedX != x || clampedY != y {
+		teleport(entity, clampedX, clampedY)
+	}
+	vx, vy := entity.GetVelocity()
+	cappedX := math.Max(-pe.MaxVelocity, math.Min(vx, pe.MaxVelocity))
+	cappedY := math.Max(-pe.MaxVelocity, math.Min(vy, pe.MaxVelocity))
+	if cappedX != vx || cappedY != vy {
+		entity.SetVelocity(cappedX, cappedY)
+	}
+	return nil
+}
+
+// selectedEntity returns the selected entity, if it still exists
+func (m Model) selectedEntity() (Entity, bool) {
+	if m.inspector.SelectedID == "" {
+		return nil, false
+	}
+	return m.entityManager.Get(m.inspector.SelectedID)
+}
+
+// entityUnderCursor returns the entity the simulation pane shows in the character
+// under the mouse. Picking follows the render mode's drawing, with the same z-order
+// and overlap policy, so a click selects what is seen.
+func (m Model) entityUnderCursor() (Entity, bool) {
+	sx, sy := m.camera.ToScreen(m.cursorX, m.cursorY)
+	return m.entityManager.EntityShown(m.camera, m.renderMode, m.overlapPolicy, sx, sy)
+}
+
+// selectNextEntity opens the inspector on the entity after the current selection
+func (m *Model) selectNextEntity() {
+	m.inspector.Open = true
+	entities := m.entityManager.GetEntities()
+	if len(entities) == 0 {
+		m.inspector.SelectedID = ""
+		return
+	}
+	next := 0
+	for i, entity := range entities {
+		if entity.GetID() == m.inspector.SelectedID {
+			next = (i + 1) % len(entities)
+			break
+		}
+	}
+	m.selectEntity(entities[next])
+}
+
+// selectEntity opens the inspector on an entity
+func (m *Model) selectEntity(entity Entity) {
+	m.inspector.Open = true
+	m.inspector.SelectedID = entity.GetID()
+	m.inspector.Editing = false
+	m.inspector.Input = ""
+	m.inspector.Error = ""
+}
+
+// handleInspectorKey handles keys while the inspector is open and reports whether it used the key
+func (m *Model) handleInspectorKey(msg tea.KeyMsg) bool {
+	in := &m.inspector
+	key := msg.String()
+
+	if in.Editing {
+		switch key {
+		case "enter":
+			if entity, ok := m.selectedEntity(); ok {
+				if err := m.setInspectorField(entity, inspectorFields[in.Field], strings.TrimSpace(in.Input)); err != nil {
+					in.Error = err.Error()
+				} else {
+					in.Error = ""
+				}
+			}
+			in.Editing, in.Input = false, ""
+		case "esc":
+			in.Editing, in.Input = false, ""
+		case "backspace":
+			if len(in.Input) > 0 {
+				runes := []rune(in.Input)
+				in.Input = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				in.Input += string(msg.Runes)
+			}
+		}
+		return true // Typing never reaches the simulation
+	}
+
+	switch key {
+	case "esc":
+		in.Open = false
+		in.Error = ""
+	case "i":
+		m.selectNextEntity()
+	case "up":
+		in.Field = (in.Field + len(inspectorFields) - 1) % len(inspectorFields)
+	case "down":
+		in.Field = (in.Field + 1) % len(inspectorFields)
+	case "left", "right":
+		m.nudgeInspectorField(key == "right")
+	case "enter":
+		if entity, ok := m.selectedEntity(); ok && inspectorFields[in.Field].Set != nil {
+			in.Editing = true
+			in.Input = inspectorFields[in.Field].Get(entity)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// nudgeInspectorField steps the current numeric field up or down
+func (m *Model) nudgeInspectorField(up bool) {
+	in := &m.inspector
+	field := inspectorFields[in.Field]
+	entity, ok := m.selectedEntity()
+	if !ok || field.Set == nil || field.Step == 0 {
+		return
+	}
+	value, err := strconv.ParseFloat(field.Get(entity), 64)
+	if err != nil {
+		return
+	}
+	if up {
+		value += field.Step
+	} else {
+		value -= field.Step
+	}
+	if err := m.setInspectorField(entity, field, strconv.FormatFloat(value, 'f', -1, 64)); err != nil {
+		in.Error = err.Error()
+	} else {
+		in.Error = ""
+	}
+}
+
+// renderInspector draws the inspector panel shown in place of the controls
+func (m Model) renderInspector() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	cursorStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00BFFF"))
+	editStyle := lipgloss.NewStyle().Underline(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+
+	lines := []string{titleStyle.Render("🔍 Inspector")}
+	entity, ok := m.selectedEntity()
+	if !ok {
+		lines = append(lines, "No entity selected. Press I to cycle or click an entity.")
+	} else {
+		for i, field := range inspectorFields {
+			marker := "  "
+			if i == m.inspector.Field {
+				marker = cursorStyle.Render("▸ ")
+			}
+			value := field.Get(entity)
+			if i == m.inspector.Field && m.inspector.Editing {
+				value = editStyle.Render(m.inspector.Input + "_")
+			}
+			lines = append(lines, fmt.Sprintf("%s%s %s", marker, labelStyle.Render(fmt.Sprintf("%-9s", field.Label)), value))
+		}
+	}
+	if m.inspector.Error != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555")).Render("⚠ "+m.inspector.Error))
+	}
+	lines = append(lines, hintStyle.Render("↑↓ Field  ←→ Nudge  Enter Edit  I Next  Click Select  Esc Close"))
+	return strings.Join(lines, "\n")
+}
+
+// highlightCell restyles a drawn cell of the selected entity in reverse video
+func highlightCell(cell Cell, color lipgloss.Color) Cell {
+	return Cell{Glyph: cell.Glyph, Color: color, Bold: true, Reverse: true}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newInspectorModel returns a sized model with two spheres
+func newInspectorModel(t *testing.T) (Model, *Sphere, *Sphere) {
+	t.Helper()
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	model.paused = true
+
+	first := NewSphere(10.5, 5.5, 1, lipgloss.Color("32"))
+	second := NewSphere(30.5, 8.5, 1, lipgloss.Color("31"))
+	model.entityManager.AddEntity(first)
+	model.entityManager.AddEntity(second)
+	return model, first, second
+}
+
+func pressInspectorKey(model Model, keys ...string) Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updatedModel, _ := model.Update(msg)
+		model = updatedModel.(Model)
+	}
+	return model
+}
+
+// inspectorFieldIndex finds a row of the inspector by label
+func inspectorFieldIndex(label string) int {
+	for i, field := range inspectorFields {
+		if field.Label == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestInspectorSelectsAndShowsFields(t *testing.T) {
+	model, first, second := newInspectorModel(t)
+
+	model = pressInspectorKey(model, "i")
+	if !model.inspector.Open || model.inspector.SelectedID != first.GetID() {
+		t.Fatal("Expected i to open the inspector on the first entity")
+	}
+	model = pressInspectorKey(model, "i")
+	if model.inspector.SelectedID != second.GetID() {
+		t.Error("Expected i to cycle to the next entity")
+	}
+
+	view := model.View()
+	for _, label := range []string{"Inspector", "ID", second.GetID(), "Mass", "VX", "Animation"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("Expected the inspector to show %q", label)
+		}
+	}
+
+	model = pressInspectorKey(model, "esc")
+	if model.inspector.Open || strings.Contains(model.View(), "Inspector") {
+		t.Error("Expected esc to close the inspector and restore the controls")
+	}
+}
+
+func TestInspectorEditsFields(t *testing.T) {
+	model, first, _ := newInspectorModel(t)
+	model = pressInspectorKey(model, "i")
+
+	// Move to X, clear the current value and type a new one
+	for model.inspector.Field != inspectorFieldIndex("X") {
+		model = pressInspectorKey(model, "down")
+	}
+	model = pressInspectorKey(model, "enter")
+	if !model.inspector.Editing {
+		t.Fatal("Expected enter to start editing the field")
+	}
+	for model.inspector.Input != "" {
+		model = pressInspectorKey(model, "backspace")
+	}
+	model = pressInspectorKey(model, "2", "0", "enter")
+	if x, _ := first.GetPosition(); x != 20 {
+		t.Errorf("Expected the typed X to move the entity, got %.2f", x)
+	}
+
+	// Nudge VX with the right arrow
+	model = pressInspectorKey(model, "down", "down")
+	model = pressInspectorKey(model, "right")
+	if vx, _ := first.GetVelocity(); vx != 1 {
+		t.Errorf("Expected a nudge to raise VX by one, got %.2f", vx)
+	}
+
+	// Bad input is reported and leaves the entity alone
+	model = pressInspectorKey(model, "enter", "backspace", "backspace", "backspace", "backspace", "x", "enter")
+	if vx, _ := first.GetVelocity(); vx != 1 || model.inspector.Error == "" {
+		t.Error("Expected an invalid number to be rejected with a message")
+	}
+
+	// Non-finite numbers are rejected, and huge ones are held to the bounds and speed cap
+	for _, value := range []string{"NaN", "Inf", "-inf"} {
+		if err := inspectorFields[inspectorFieldIndex("VX")].Set(first, value); err == nil {
+			t.Errorf("Expected %s to be rejected", value)
+		}
+		if err := inspectorFields[inspectorFieldIndex("Mass")].Set(first, value); err == nil || math.IsNaN(first.GetMass()) {
+			t.Errorf("Expected %s to be rejected as a mass", value)
+		}
+	}
+	model = pressInspectorKey(model, "enter", "backspace", "backspace", "backspace", "backspace", "1", "e", "3", "0", "8", "enter")
+	if vx, _ := first.GetVelocity(); vx != model.physicsEngine.MaxVelocity || model.inspector.Error != "" {
+		t.Errorf("Expected a huge VX to be capped, got %g", vx)
+	}
+	for model.inspector.Field != inspectorFieldIndex("X") {
+		model = pressInspectorKey(model, "up")
+	}
+	model = pressInspectorKey(model, "enter", "backspace", "backspace", "backspace", "backspace", "backspace", "-", "5", "0", "enter")
+	if x, _ := first.GetPosition(); x < model.physicsEngine.MinX || x > model.physicsEngine.MaxX {
+		t.Errorf("Expected a typed X outside the world to be clamped, got %.2f", x)
+	}
+
+	// Resizing a sphere keeps its radius in step
+	for model.inspector.Field != inspectorFieldIndex("Size") {
+		model = pressInspectorKey(model, "up")
+	}
+	pressInspectorKey(model, "right")
+	if first.GetSize() != 2 || first.GetRadius() != effectiveSizeFor(2)/2 {
+		t.Errorf("Expected size 2 with a matching radius, got %d and %.2f", first.GetSize(), first.GetRadius())
+	}
+	largest := entitySizes[len(entitySizes)-1]
+	if err := inspectorFields[inspectorFieldIndex("Size")].Set(first, "100000"); err == nil || first.GetSize() != 2 {
+		t.Error("Expected a size past the largest offered to be rejected")
+	}
+	if err := inspectorFields[inspectorFieldIndex("Size")].Set(first, strconv.Itoa(largest)); err != nil || first.GetSize() != largest {
+		t.Errorf("Expected the largest offered size to be accepted, got %v", err)
+	}
+}
+
+func TestInspectorClickSelectsAndHighlights(t *testing.T) {
+	model, first, second := newInspectorModel(t)
+	model = pressInspectorKey(model, "i")
+
+	updatedModel, _ := model.Update(tea.MouseMsg{
+		X: SimGridOriginX + 30, Y: SimGridOriginY + 8,
+		Button: tea.MouseButtonLeft, Action: tea.MouseActionPress,
+	})
+	model = updatedModel.(Model)
+	if model.inspector.SelectedID != second.GetID() {
+		t.Fatal("Expected a click to select the entity under the cursor")
+	}
+	if model.slingshot.Aiming {
+		t.Error("Expected a selecting click not to start the slingshot")
+	}
+
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	model.renderSimulation()
+	if cell := buffer.Cell(30, 8); cell != highlightCell(second.Render(), second.GetColor()) || !cell.Reverse {
+		t.Errorf("Expected the selected entity to be drawn highlighted, got %+v", cell)
+	}
+
+	model.entityManager.RemoveEntity(second.GetID())
+	if !strings.Contains(model.View(), "No entity selected") {
+		t.Error("Expected the inspector to notice the entity was removed")
+	}
+
+	// Zoomed out, a character shows a 4×4 block and a click picks anything in it
+	model.camera.Zoom = 4
+	screenX, screenY := model.camera.ToScreen(first.GetDisplayPosition())
+	updatedModel, _ = model.Update(tea.MouseMsg{
+		X: SimGridOriginX + screenX, Y: SimGridOriginY + screenY,
+		Button: tea.MouseButtonLeft, Action: tea.MouseActionPress,
+	})
+	model = updatedModel.(Model)
+	if model.inspector.SelectedID != first.GetID() {
+		t.Error("Expected a zoomed-out click to select the entity anywhere in the character's block")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -463,3 +464,46 @@ func TestControlPanelIntegration(t *testing.T) {
 }
 
 // Helper functions are defined in controls_test.go - we'll use those
+
+// Test tilt mode rotating gravity with the arrow keys
+func TestTiltModeRotatesGravity(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	model = updatedModel.(Model)
+
+	press := func(key tea.KeyMsg) {
+		updatedModel, _ := model.Update(key)
+		model = updatedModel.(Model)
+	}
+
+	// Without tilt mode the arrows still navigate the control panel
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if model.physicsEngine.GetGravityAngle() != 90 {
+		t.Fatal("Expected arrows to leave gravity alone outside tilt mode")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if model.physicsEngine.GetGravityAngle() != 90+tiltStep {
+		t.Errorf("Expected left to tilt gravity to %.0f°, got %.0f°", 90+tiltStep, model.physicsEngine.GetGravityAngle())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	if model.physicsEngine.GravityArrow() != "↑" {
+		t.Errorf("Expected up to flip gravity, got %s", model.physicsEngine.GravityArrow())
+	}
+	if model.materials.DownY != -1 {
+		t.Error("Expected the material layer to follow gravity")
+	}
+
+	model.renderer = NewCellBuffer()
+	view := model.renderSimulation()
+	if !strings.Contains(view, "Gravity: 25.0 ↑") || !strings.Contains(view, "Tilt: 285°") {
+		t.Error("Expected the status line to show the gravity direction")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if model.physicsEngine.GetGravityAngle() != 90 {
+		t.Errorf("Expected down to restore gravity, got %.0f°", model.physicsEngine.GetGravityAngle())
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+// WallEdge identifies which wall of the simulation an entity hit
+type WallEdge int
+
+const (
+	WallLeft WallEdge = iota
+	WallRight
+	WallTop
+	WallBottom
+)
+
+// String returns the edge's name
+func (edge WallEdge) String() string {
+	return [...]string{"left", "right", "top", "bottom"}[edge]
+}
+
+// Lifecycle hooks an entity can implement to react to its own events.
+// Each is optional; implement only the ones a type needs.
+
+type spawnHook interface {
+	OnSpawn()
+}
+
+type collideHook interface {
+	OnCollide(other Entity, impulse float64)
+}
+
+type wallHitHook interface {
+	OnWallHit(edge WallEdge)
+}
+
+type sleepHook interface {
+	OnSleep()
+}
+
+type despawnHook interface {
+	OnDespawn()
+}
+
+// LifecycleObserver receives lifecycle events for every entity. Nil funcs are skipped.
+type LifecycleObserver struct {
+	OnSpawn   func(entity Entity)
+	OnCollide func(entity, other Entity, impulse float64)
+	OnWallHit func(entity Entity, edge WallEdge)
+	OnSleep   func(entity Entity)
+	OnDespawn func(entity Entity)
+}
+
+// Lifecycle dispatches entity lifecycle events. For every event the entity's own
+// hook runs first, then observers in the order they were added. A nil Lifecycle
+// still calls the entity's own hooks.
+//
+// Events fire in this order over an entity's life:
+//   - OnSpawn when EntityManager.AddEntity adds it
+//   - during each physics step: OnWallHit when it bounces off a wall, OnSleep when it
+//     comes to rest, and OnCollide for both entities (first, then second) of each
+//     resolved collision, with the impulse magnitude exchanged
+//   - OnDespawn when RemoveEntity, Clear or another removal method takes it out
+//
+// Hooks run after the manager's lock is released, so they may add or remove entities.
+type Lifecycle struct {
+	observers []LifecycleObserver
+}
+
+// NewLifecycle creates a dispatcher with no observers
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// Observe registers an observer for every entity's events
+func (l *Lifecycle) Observe(observer LifecycleObserver) {
+	l.observers = append(l.observers, observer)
+}
+
+// observe returns the registered observers; nil-safe
+func (l *Lifecycle) observe() []LifecycleObserver {
+	if l == nil {
+		return nil
+	}
+	return l.observers
+}
+
+func (l *Lifecycle) spawned(entity Entity) {
+	if hook, ok := entity.(spawnHook); ok {
+		hook.OnSpawn()
+	}
+	for _, o := range l.observe() {
+		if o.OnSpawn != nil {
+			o.OnSpawn(entity)
+		}
+	}
+}
+
+func (l *Lifecycle) collided(entity, other Entity, impulse float64) {
+	if hook, ok := entity.(collideHook); ok {
+		hook.OnCollide(other, impulse)
+	}
+	for _, o := range l.observe() {
+		if o.OnCollide != nil {
+			o.OnCollide(entity, other, impulse)
+		}
+	}
+}
+
+func (l *Lifecycle) hitWall(entity Entity, edge WallEdge) {
+	if hook, ok := entity.(wallHitHook); ok {
+		hook.OnWallHit(edge)
+	}
+	for _, o := range l.observe() {
+		if o.OnWallHit != nil {
+			o.OnWallHit(entity, edge)
+		}
+	}
+}
+
+func (l *Lifecycle) slept(entity Entity) {
+	if hook, ok := entity.(sleepHook); ok {
+		hook.OnSleep()
+	}
+	for _, o := range l.observe() {
+		if o.OnSleep != nil {
+			o.OnSleep(entity)
+		}
+	}
+}
+
+func (l *Lifecycle) despawned(entities ...Entity) {
+	for _, entity := range entities {
+		if hook, ok := entity.(despawnHook); ok {
+			hook.OnDespawn()
+		}
+		for _, o := range l.observe() {
+			if o.OnDespawn != nil {
+				o.OnDespawn(entity)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// hookedSphere records its own lifecycle hooks into a shared log
+type hookedSphere struct {
+	*Sphere
+	log *[]string
+}
+
+func (h *hookedSphere) OnSpawn()   { *h.log = append(*h.log, "spawn "+h.GetID()) }
+func (h *hookedSphere) OnDespawn() { *h.log = append(*h.log, "despawn "+h.GetID()) }
+func (h *hookedSphere) OnSleep()   { *h.log = append(*h.log, "sleep") }
+func (h *hookedSphere) OnWallHit(edge WallEdge) {
+	*h.log = append(*h.log, "wall "+edge.String())
+}
+func (h *hookedSphere) OnCollide(other Entity, impulse float64) {
+	*h.log = append(*h.log, "collide "+h.GetID()+" with "+other.GetID())
+}
+
+func newHookedSphere(x, y float64, log *[]string) *hookedSphere {
+	return &hookedSphere{Sphere: NewSphere(x, y, 1, lipgloss.Color("32")), log: log}
+}
+
+func TestSpawnAndDespawnOrder(t *testing.T) {
+	var log []string
+	manager := NewEntityManager()
+	manager.Lifecycle.Observe(LifecycleObserver{
+		OnSpawn:   func(e Entity) { log = append(log, "observed spawn "+e.GetID()) },
+		OnDespawn: func(e Entity) { log = append(log, "observed despawn "+e.GetID()) },
+	})
+
+	a, b, c := newHookedSphere(1, 1, &log), newHookedSphere(2, 2, &log), newHookedSphere(3, 3, &log)
+	manager.AddEntity(a)
+	manager.AddEntity(b)
+	manager.AddEntity(a) // Already managed: no second spawn
+	manager.AddEntity(c)
+	manager.RemoveEntity(b.GetID())
+	manager.Clear()
+
+	expected := []string{
+		"spawn sphere_1", "observed spawn sphere_1",
+		"spawn sphere_2", "observed spawn sphere_2",
+		"spawn sphere_3", "observed spawn sphere_3",
+		"despawn sphere_2", "observed despawn sphere_2",
+		"despawn sphere_1", "observed despawn sphere_1",
+		"despawn sphere_3", "observed despawn sphere_3",
+	}
+	if fmt.Sprint(log) != fmt.Sprint(expected) {
+		t.Errorf("Expected entity hooks before observers in call order:\n got %v\nwant %v", log, expected)
+	}
+}
+
+func TestHooksMayChangeTheManager(t *testing.T) {
+	manager := NewEntityManager()
+	manager.Lifecycle.Observe(LifecycleObserver{
+		OnDespawn: func(e Entity) {
+			// Split: a removed sphere leaves a smaller one behind
+			if e.GetSize() > 1 {
+				x, y := e.GetPosition()
+				manager.AddEntity(NewSphere(x, y, e.GetSize()-1, e.GetColor()))
+			}
+		},
+	})
+	big := NewSphere(5, 5, 3, lipgloss.Color("32"))
+	manager.AddEntity(big)
+
+	manager.RemoveEntity(big.GetID())
+	if manager.Count() != 1 || manager.GetEntities()[0].GetSize() != 2 {
+		t.Error("Expected the despawn observer to be able to add entities")
+	}
+}
+
+func TestPhysicsLifecycleEvents(t *testing.T) {
+	var log []string
+	pe := NewPhysicsEngine(40, 20)
+	pe.Lifecycle = NewLifecycle()
+	var observedWalls int
+	pe.Lifecycle.Observe(LifecycleObserver{OnWallHit: func(Entity, WallEdge) { observedWalls++ }})
+
+	ball := newHookedSphere(20, 5, &log)
+	for i := 0; i < 500; i++ {
+		pe.ApplyPhysics([]Entity{ball})
+	}
+
+	if len(log) == 0 || log[0] != "wall bottom" {
+		t.Fatalf("Expected the falling ball to hit the bottom wall first, got %v", log)
+	}
+	if log[len(log)-1] != "sleep" {
+		t.Errorf("Expected the ball to fall asleep once it came to rest, got %v", log)
+	}
+	sleeps := 0
+	for _, event := range log {
+		if event == "sleep" {
+			sleeps++
+		}
+	}
+	if sleeps != 1 {
+		t.Errorf("Expected a single sleep event while resting, got %d", sleeps)
+	}
+	if observedWalls != len(log)-1 {
+		t.Errorf("Expected observers to see every wall hit, got %d of %d", observedWalls, len(log)-1)
+	}
+
+	// Pushing it wakes it, and it sleeps again once it settles
+	ball.SetVelocity(0, -10)
+	for i := 0; i < 500; i++ {
+		pe.ApplyPhysics([]Entity{ball})
+	}
+	if log[len(log)-1] != "sleep" || len(log) <= sleeps+observedWalls {
+		t.Errorf("Expected new wall hits and another sleep after waking, got %v", log)
+	}
+}
+
+func TestCollisionHooksBothEntities(t *testing.T) {
+	var log []string
+	var impulses []float64
+	pe := NewPhysicsEngine(80, 40)
+	pe.Lifecycle = NewLifecycle()
+	pe.Lifecycle.Observe(LifecycleObserver{OnCollide: func(_, _ Entity, impulse float64) { impulses = append(impulses, impulse) }})
+
+	left, right := newHookedSphere(10, 10, &log), newHookedSphere(10.5, 10, &log)
+	left.SetID("left")
+	right.SetID("right")
+	left.SetVelocity(5, 0)
+	right.SetVelocity(-5, 0)
+
+	pe.HandleEntityCollisions([]Entity{left, right})
+
+	if fmt.Sprint(log) != "[collide left with right collide right with left]" {
+		t.Errorf("Expected both entities to hear the collision, first then second, got %v", log)
+	}
+	if len(impulses) != 2 || impulses[0] <= 0 || impulses[0] != impulses[1] {
+		t.Errorf("Expected both to see the same positive impulse, got %v", impulses)
+	}
+}
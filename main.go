@@ -2,7 +2,7 @@
 // using the Bubble Tea framework. It features real-time particle physics,
 // smooth spring-based animations, and an interactive control interface.
 //
-// The simulation supports multiple entity types (spheres and sprites) with
+// The simulation supports multiple entity types (spheres, sprites and boids) with
 // configurable physics parameters including gravity, bounce, air resistance,
 // and collision detection. The interface is responsive and adapts to various
 // terminal sizes, providing an optimal experience from compact 50-character
@@ -20,19 +20,42 @@
 //	go run .
 //	# or
 //	go build -o physics-sim . && ./physics-sim
+//	./physics-sim -behaviors behaviors.json  # load extra behaviors
+//	./physics-sim -sprites sprites.json      # load animated sprites as new entity types
+//	./physics-sim -world 240x80              # fixed world size, explored with the camera
+//	./physics-sim -render braille            # 2×4 dots per character cell
+//	./physics-sim -plain                     # plain ASCII simulation pane, no escape codes
 //
 // Controls:
 //   - a/s: Add sphere/sprite entities
+//   - o: Add a boid that flocks with other boids
+//   - mouse: Press, drag back and release in the simulation to launch an entity
+//   - e: Cycle the entity type launched with the mouse
+//   - v: Cycle the behavior attached to new entities
+//   - h: Toggle ghost spawning (new entities pass through other entities)
+//   - k/j/K: Place a trigger zone at the brush / cycle zone kind / clear zones
+//   - m/n: Cycle the material brush / paint at the brush (shift+arrows move it)
 //   - c: Clear all entities
 //   - p: Pause/resume simulation
 //   - r: Reset simulation
 //   - g/b/z/x: Cycle gravity/bounce/size/color parameters
+//   - w: Toggle tilt mode (left/right rotate gravity, up flips it, down resets it)
+//   - d: Cycle the animation spring preset (snappy, smooth, wobbly, none)
+//   - y/Y: Cycle motion trails off/dots/shades / cycle trail length
+//   - i: Inspect the next entity (or click one while inspecting); arrows pick and nudge fields, enter edits, esc closes
 //   - f: Toggle performance monitoring mode
-//   - t: Run stress test (add 20 entities)
+//   - F: Toggle impact effects (flashes, squashed glyphs and sparks)
+//   - O: Cycle what overlapping entities show: the topmost, the heaviest or a count
+//   - R: Cycle render modes: glyphs, half blocks (1×2 dots per cell) and braille (2×4)
+//   - ctrl+arrows/+/-/@/0: Pan/zoom in/zoom out/follow the inspected entity/reset the camera
+//     (the mouse wheel zooms and a right-button drag pans)
+//   - u/U: Place a prefab (pyramid, lattice, chain, ring, cloud) at the brush / cycle prefab
+//   - t: Run stress test (add a cloud of 20 entities)
 //   - q: Quit application
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
@@ -64,6 +87,11 @@ const (
 	UltraCompactWidth  = 30                     // Width threshold for ultra-compact mode
 	CompactWidth       = 80                     // Width threshold for compact mode
 	StandardWidth      = 120                    // Width threshold for standard mode
+
+	// Simulation grid origin within the styled simulation pane
+	// (border + padding, then the title and separator lines)
+	SimGridOriginX = 3
+	SimGridOriginY = 4
 )
 
 // tickMsg is sent periodically to update the simulation
@@ -85,6 +113,7 @@ type Model struct {
 	entityManager   *EntityManager
 	physicsEngine   *PhysicsEngine
 	animationEngine *AnimationEngine
+	effects         *ImpactEffects
 	paused          bool
 
 	// UI state
@@ -95,6 +124,47 @@ type Model struct {
 	selectedGravity    float64
 	selectedEntitySize int
 	selectedColorIndex int
+	selectedBehavior   int  // Index into behaviorNames
+	spawnGhosts        bool // New entities pass through other entities
+	tiltMode           bool // Arrow keys rotate gravity instead of navigating
+	trailMode          TrailMode
+	trailLengthIndex   int // Index into trailLengths
+	prefabKind         PrefabKind // Arranged group placed at the brush with u
+	springPreset       int        // Index into springPresets
+	renderMode         RenderMode // Glyphs, or dots for sub-cell resolution
+	overlapPolicy      OverlapPolicy // What a cell shows when entities overlap; glyph mode only
+	renderer           Renderer      // Turns simulation frames into output
+	selectedEntityType EntityType
+
+	// Behaviors that can be attached to new entities
+	behaviorSpecs map[string]BehaviorSpec
+	behaviorNames []string
+
+	// Mouse cursor in simulation coordinates (for behaviors)
+	cursorX, cursorY float64
+	cursorInSim      bool
+
+	// Press-drag-release launcher in the simulation pane
+	slingshot Slingshot
+
+	// View onto the world, and its size when fixed (0 follows the pane)
+	camera                  Camera
+	worldWidth, worldHeight int
+	panning                 bool // Right-button drag in progress
+	panFromX, panFromY      int  // Screen cell the drag last moved from
+
+	// Selected-entity inspector shown in place of the controls
+	inspector Inspector
+
+	// Trigger zones and the score they award
+	sensors      *SensorManager
+	sensorPreset int // Index into sensorPresets for new zones
+	score        int
+
+	// Falling-sand material layer and the brush used to paint it
+	materials      *MaterialGrid
+	materialBrush  int // Index into materialBrushes; 0 means painting is off
+	brushX, brushY int
 
 	// Performance monitoring
 	performanceMode bool
@@ -185,17 +255,39 @@ func initialModel() Model {
 	// Create control panel with default dimensions (will be updated when terminal size is known)
 	controlPanel := NewControlPanel(80, 10)
 
+	// Material layer is sized to the render grid once the terminal size is known
+	materials := NewMaterialGrid(0, 0)
+	physicsEngine.Materials = materials
+
+	// Physics and the entity manager report lifecycle events to the same observers
+	entityManager := NewEntityManager()
+	physicsEngine.Lifecycle = entityManager.Lifecycle
+
+	// Impacts drive cosmetic flashes, squashes and sparks
+	effects := NewImpactEffects()
+	entityManager.Lifecycle.Observe(effects.Observer())
+
 	return Model{
-		entityManager:   NewEntityManager(),
+		entityManager:   entityManager,
 		physicsEngine:   physicsEngine,
 		animationEngine: animationEngine,
+		effects:         effects,
+		camera:          NewCamera(),
+		renderer:        LipglossRenderer{},
 		paused:          false,
 		ready:           false,
 		controlPanel:    controlPanel,
+		materials:       materials,
+		sensors:         NewSensorManager(),
 		// Initialize parameter controls with defaults
 		selectedGravity:    25.0, // Normal gravity
 		selectedEntitySize: 1,    // Small size
+		selectedEntityType: SphereType,
 		selectedColorIndex: 0,    // First color (Green)
+		selectedBehavior:   0,    // No behavior
+		springPreset:       defaultSpringPreset,
+		behaviorSpecs:      builtinBehaviorSpecs(),
+		behaviorNames:      append([]string(nil), builtinBehaviorNames...),
 		// Initialize performance monitoring
 		performanceMode: false,
 		frameCount:      0,
@@ -230,18 +322,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updatePaneDimensions()
 		m.ready = true
 
-		// Update physics engine bounds to match render grid
-		renderGridHeight := m.simHeight - 8 // Must match renderSimulation grid calculation
-		m.physicsEngine.UpdateBounds(float64(m.simWidth), float64(renderGridHeight))
+		// Update physics engine bounds to match the world, which follows the
+		// render grid unless its size is fixed
+		worldWidth, worldHeight := m.worldSize()
+		m.physicsEngine.UpdateBounds(float64(worldWidth), float64(worldHeight))
 
 		// Handle entities at new boundaries naturally (bounce instead of clamp)
-		m.handleBoundaryResize(float64(m.simWidth), float64(renderGridHeight))
+		m.handleBoundaryResize(float64(worldWidth), float64(worldHeight))
+
+		// Keep the material layer aligned with the world
+		m.materials.Resize(m.materialGridSize())
+		m.clampCamera()
 
 		// Force immediate animation update to sync with new boundaries
-		entities := m.entityManager.GetEntities()
-		for _, entity := range entities {
-			entity.UpdateAnimation(m.animationEngine)
-		}
+		m.animationEngine.UpdateEntities(m.entityManager.GetEntities())
 
 		// Update control panel dimensions and responsive mode
 		var ctrlContentWidth int
@@ -271,14 +365,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Update physics simulation if not paused
 			if !m.paused {
+				m.materials.Step()
+				ApplyBehaviors(entities, m.world(), m.physicsEngine.DeltaTime)
 				m.physicsEngine.ApplyPhysics(entities)
 				m.physicsEngine.HandleEntityCollisions(entities)
+				m.entityManager.RebuildSpatialIndex()
+				m.handleSensorEvents(m.sensors.Update(m.entityManager.QueryRect))
 			}
 
 			// Always update animations for smooth movement (even when paused)
-			for _, entity := range entities {
-				entity.UpdateAnimation(m.animationEngine)
-			}
+			m.animationEngine.UpdateEntities(entities)
+			m.effects.Update(m.animationEngine)
+			m.followCamera()
 		}
 
 		// Continue ticking
@@ -289,6 +387,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleButtonAction(msg.Action)
 
 	case tea.KeyMsg:
+		// The open inspector takes field navigation and editing keys
+		if m.inspector.Open && m.handleInspectorKey(msg) {
+			return m, nil
+		}
+
+		// In tilt mode the arrow keys rotate the box instead of navigating
+		if m.tiltMode && m.tiltGravity(msg.String()) {
+			return m, nil
+		}
+
 		// Forward to control panel first for navigation (tab, enter, etc.)
 		if msg.String() == "tab" || msg.String() == "shift+tab" ||
 			msg.String() == "right" || msg.String() == "left" ||
@@ -301,41 +409,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		case "a":
-			// Add sphere with selected parameters
-			if m.entityManager.Count() < m.maxEntityLimit { // Dynamic entity limit
-				x := float64(rand.Intn(m.simWidth-4) + 2) // Keep away from borders
-				y := float64(2 + rand.Intn(3))            // Start near top
-				size := m.selectedEntitySize
-				color := m.getSelectedColor()
-
-				sphere := NewSphere(x, y, size, color)
-
-				// Add some initial random velocity for more interesting physics
-				m.physicsEngine.AddRandomVelocity(sphere, 5.0)
-
-				m.entityManager.AddEntity(sphere)
-			}
+		// Spawn keys come from the entity type registry
+		if info, ok := entityTypeForKey(msg.String()); ok {
+			m.spawnEntity(info.Type)
 			return m, nil
-		case "s":
-			// Add sprite with selected parameters
-			if m.entityManager.Count() < m.maxEntityLimit { // Dynamic entity limit
-				x := float64(rand.Intn(m.simWidth-4) + 2) // Keep away from borders
-				y := float64(2 + rand.Intn(3))            // Start near top
-				size := m.selectedEntitySize
-				color := m.getSelectedColor()
-
-				sprite := NewSprite(x, y, size, color, "") // Random symbol
-
-				// Add some initial random velocity for more interesting physics
-				m.physicsEngine.AddRandomVelocity(sprite, 5.0)
+		}
 
-				m.entityManager.AddEntity(sprite)
-			}
+		// Pan, zoom and follow with the camera
+		if m.handleCameraKey(msg.String()) {
 			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
 		case "c":
 			// Clear all entities
 			m.entityManager.Clear()
@@ -355,6 +442,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			// Reset simulation
 			m.entityManager.Clear()
+			m.materials.Clear()
+			m.sensors.Clear()
+			m.score = 0
 			m.paused = false
 			m.physicsEngine.Resume()
 			m.controlPanel.UpdatePauseButton(m.paused)
@@ -363,6 +453,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Cycle gravity settings
 			m.cycleGravity()
 			return m, nil
+		case "e":
+			// Cycle the entity type launched with the mouse
+			m.cycleEntityType()
+			return m, nil
+		case "w":
+			// Toggle tilt mode for rotating gravity with the arrow keys
+			m.tiltMode = !m.tiltMode
+			return m, nil
 		case "b":
 			// Cycle bounce settings
 			currentBounce := m.physicsEngine.GetRestitution()
@@ -385,6 +483,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Cycle entity color for new entities
 			m.cycleEntityColor()
 			return m, nil
+		case "v":
+			// Cycle behavior for new entities
+			m.cycleBehavior()
+			return m, nil
+		case "h":
+			// Toggle ghost spawning: new entities pass through each other but not walls
+			m.spawnGhosts = !m.spawnGhosts
+			return m, nil
+		case "k":
+			// Place a trigger zone of the selected kind at the brush cursor
+			m.placeSensor(m.brushX, m.brushY)
+			return m, nil
+		case "j":
+			// Cycle the kind of trigger zone placed with k
+			m.sensorPreset = (m.sensorPreset + 1) % len(sensorPresets)
+			return m, nil
+		case "K":
+			// Remove all trigger zones
+			m.sensors.Clear()
+			return m, nil
+		case "m":
+			// Cycle the material brush (off, sand, water, stone, fire, smoke, erase)
+			m.materialBrush = (m.materialBrush + 1) % len(materialBrushes)
+			return m, nil
+		case "n":
+			// Paint with the material brush at the brush cursor
+			m.paintMaterial(m.brushX, m.brushY)
+			return m, nil
+		case "shift+left", "shift+right", "shift+up", "shift+down":
+			// Move the keyboard brush cursor
+			m.moveBrush(msg.String())
+			return m, nil
+		case "u":
+			// Place the selected prefab at the brush cursor
+			m.placePrefab(float64(m.brushX)+0.5, float64(m.brushY)+0.5)
+			return m, nil
+		case "U":
+			// Cycle the prefab placed with u
+			m.prefabKind = (m.prefabKind + 1) % PrefabKind(len(prefabNames))
+			return m, nil
+		case "F":
+			// Toggle impact flashes, squashes and sparks
+			m.effects.SetEnabled(!m.effects.Enabled, m.entityManager.GetEntities())
+			return m, nil
+		case "d":
+			m.cycleSpringPreset()
+			return m, nil
+		case "O":
+			// Cycle what a cell shows when entities overlap
+			m.overlapPolicy = (m.overlapPolicy + 1) % OverlapPolicy(len(overlapPolicyNames))
+			return m, nil
+		case "R":
+			// Cycle glyph, half-block and braille rendering
+			m.setRenderMode((m.renderMode + 1) % RenderMode(len(renderModeNames)))
+			return m, nil
+		case "i":
+			m.selectNextEntity()
+			return m, nil
+		case "y":
+			m.trailMode = (m.trailMode + 1) % TrailMode(len(trailModeNames))
+			m.applyTrailLength()
+			return m, nil
+		case "Y":
+			m.trailLengthIndex = (m.trailLengthIndex + 1) % len(trailLengths)
+			m.applyTrailLength()
+			return m, nil
 		case "f":
 			// Toggle performance mode display
 			m.performanceMode = !m.performanceMode
@@ -411,6 +575,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.MouseMsg:
+		// Track the cursor for behaviors that react to it
+		m.cursorX, m.cursorY, m.cursorInSim = m.screenToSim(msg.X, msg.Y)
+
+		// The wheel zooms and a right-button drag pans the camera
+		if m.handleCameraMouse(msg) {
+			return m, nil
+		}
+
+		// The brush follows the mouse; holding the left button paints
+		if m.cursorInSim {
+			m.brushX, m.brushY = int(m.cursorX), int(m.cursorY)
+			if msg.Button == tea.MouseButtonLeft && msg.Action != tea.MouseActionRelease && m.materialBrush > 0 {
+				m.paintMaterial(m.brushX, m.brushY)
+				return m, nil
+			}
+		}
+
+		// While inspecting, clicking an entity selects it instead of launching
+		if m.inspector.Open && m.cursorInSim && msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+			if entity, ok := m.entityUnderCursor(); ok {
+				m.selectEntity(entity)
+				return m, nil
+			}
+		}
+
+		// Press, drag and release in the simulation to launch an entity
+		if m.handleSlingshot(msg) {
+			return m, nil
+		}
+
 		// Forward mouse messages to control panel
 		var cmd tea.Cmd
 		updatedModel, cmd := m.controlPanel.Update(msg)
@@ -425,41 +619,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleButtonAction processes button activation events
 func (m Model) handleButtonAction(action ButtonAction) (tea.Model, tea.Cmd) {
-	switch action {
-	case AddSphereAction:
-		// Add sphere with selected parameters
-		if m.entityManager.Count() < m.maxEntityLimit { // Dynamic entity limit
-			x := float64(rand.Intn(m.simWidth-4) + 2) // Keep away from borders
-			y := float64(2 + rand.Intn(3))            // Start near top
-			size := m.selectedEntitySize
-			color := m.getSelectedColor()
-
-			sphere := NewSphere(x, y, size, color)
-
-			// Add some initial random velocity for more interesting physics
-			m.physicsEngine.AddRandomVelocity(sphere, 5.0)
-
-			m.entityManager.AddEntity(sphere)
-		}
-		return m, nil
-
-	case AddSpriteAction:
-		// Add sprite with selected parameters
-		if m.entityManager.Count() < m.maxEntityLimit { // Dynamic entity limit
-			x := float64(rand.Intn(m.simWidth-4) + 2) // Keep away from borders
-			y := float64(2 + rand.Intn(3))            // Start near top
-			size := m.selectedEntitySize
-			color := m.getSelectedColor()
-
-			sprite := NewSprite(x, y, size, color, "") // Random symbol
-
-			// Add some initial random velocity for more interesting physics
-			m.physicsEngine.AddRandomVelocity(sprite, 5.0)
-
-			m.entityManager.AddEntity(sprite)
-		}
+	// Add buttons are generated from the entity type registry
+	if entityType, ok := entityTypeForAction(action); ok {
+		m.spawnEntity(entityType)
 		return m, nil
+	}
 
+	switch action {
 	case ClearAllAction:
 		// Clear all entities
 		m.entityManager.Clear()
@@ -481,11 +647,18 @@ func (m Model) handleButtonAction(action ButtonAction) (tea.Model, tea.Cmd) {
 	case ResetAction:
 		// Reset simulation
 		m.entityManager.Clear()
+		m.materials.Clear()
+		m.sensors.Clear()
+		m.score = 0
 		m.paused = false
 		m.physicsEngine.Resume()
 		m.controlPanel.UpdatePauseButton(m.paused)
 		return m, nil
 
+	case SpringsAction:
+		m.cycleSpringPreset()
+		return m, nil
+
 	case GravityAction:
 		// Cycle gravity settings
 		m.cycleGravity()
@@ -533,6 +706,9 @@ func (m Model) View() string {
 
 		// Ultra-minimal control content for small screens
 		ctrlContent := m.renderMinimalControls()
+		if m.inspector.Open {
+			ctrlContent = m.renderInspector()
+		}
 
 		// Simple concatenation with minimal separator
 		return simContent + "\n---\n" + ctrlContent
@@ -571,6 +747,9 @@ func (m Model) View() string {
 
 	// Create control pane content
 	ctrlContent := m.renderControls()
+	if m.inspector.Open {
+		ctrlContent = m.renderInspector()
+	}
 	// Account for styling overhead with responsive constraints
 	var ctrlStyleWidth int
 	if isTestEnv {
@@ -664,29 +843,41 @@ func (m *Model) updatePaneDimensions() {
 	m.ctrlWidth = usableWidth
 }
 
-// renderSimulation creates the simulation pane content with enhanced visual polish
+// simContentWidth returns the usable width inside the simulation pane (accounting for styling overhead)
+func (m Model) simContentWidth() int {
+	if m.simWidth <= UltraCompactWidth {
+		return max(5, m.simWidth-20) // Very aggressive for small screens
+	} else if m.simWidth <= 80 {
+		return max(15, m.simWidth-8) // Less aggressive for medium screens
+	}
+	return max(20, m.simWidth-10) // Normal reduction for large screens
+}
+
+// simGridSize returns the dimensions of the cell grid drawn by renderSimulation
+func (m Model) simGridSize() (width, height int) {
+	height = m.simHeight - 8 // Account for enhanced styling and spacing
+	if height <= 0 {
+		height = 1
+	}
+	return max(1, m.simContentWidth()), height
+}
+
+// renderSimulation creates the simulation pane content with the model's renderer
 func (m Model) renderSimulation() string {
 	// For ultra-small terminals, return minimal simulation content
 	if m.termWidth <= UltraCompactWidth {
 		return m.renderMinimalSimulation()
 	}
+	return m.renderer.Render(m.simulationFrame())
+}
 
+// simulationFrame describes what the simulation pane shows this frame
+func (m Model) simulationFrame() Frame {
 	// Calculate actual content width (accounting for styling overhead)
-	var contentWidth int
-	if m.simWidth <= UltraCompactWidth {
-		contentWidth = max(5, m.simWidth-20) // Very aggressive for small screens
-	} else if m.simWidth <= 80 {
-		contentWidth = max(15, m.simWidth-8) // Less aggressive for medium screens  
-	} else {
-		contentWidth = max(20, m.simWidth-10) // Normal reduction for large screens
-	}
-	var lines []string
+	contentWidth := m.simContentWidth()
 
 	// Create a 2D grid for entity positioning
-	gridHeight := m.simHeight - 8 // Account for enhanced styling and spacing
-	if gridHeight <= 0 {
-		gridHeight = 1
-	}
+	gridWidth, gridHeight := m.simGridSize()
 
 	// Enhanced title with visual flair and responsive mode indicator
 	var titleText string
@@ -718,65 +909,129 @@ func (m Model) renderSimulation() string {
 		}
 	}
 
-	title := titleStyle.Width(contentWidth).Render(titleText)
-	lines = append(lines, title)
+	// Each kind of content draws into its own layer, flattened bottom to top
+	comp := NewCompositor(gridWidth, gridHeight)
 
-	// Add decorative separator (ensure it fits)
-	separatorWidth := max(1, contentWidth-4)
-	separator := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4A90E2")).
-		Render(strings.Repeat("─", separatorWidth))
-	lines = append(lines, "  "+separator)
-
-	grid := make([][]string, gridHeight)
-	gridWidth := max(1, contentWidth)
-	for i := range grid {
-		grid[i] = make([]string, gridWidth)
-		for j := range grid[i] {
-			grid[i][j] = " "
+	// Everything below is in world coordinates, drawn through the camera
+	cam := m.camera
+
+	// Draw trigger zone outlines
+	fields := comp.Layer(LayerFields)
+	for _, sensor := range m.sensors.Sensors() {
+		for y := max(0, int(sensor.Y)); y < min(m.materials.Height, int(sensor.Y+sensor.Height)); y++ {
+			for x := max(0, int(sensor.X)); x < min(m.materials.Width, int(sensor.X+sensor.Width)); x++ {
+				if cell := sensor.Render(x, y); !cell.Empty() {
+					cam.plotCell(fields, x, y, cell)
+				}
+			}
 		}
 	}
 
-	// Place entities on the grid using animated display positions
-	for _, entity := range m.entityManager.GetEntities() {
-		x, y := entity.GetDisplayPosition() // Use animated position for rendering
-		gridX := int(x)
-		gridY := int(y)
+	// Draw motion trails
+	drawTrails(comp.Layer(LayerTrails), cam, m.entityManager.GetEntities(), m.trailMode)
 
-		if gridY >= 0 && gridY < len(grid) && gridX >= 0 && gridX < len(grid[0]) {
-			grid[gridY][gridX] = entity.Render()
+	// Draw the material layer
+	obstacles := comp.Layer(LayerObstacles)
+	for sy := range obstacles {
+		for sx := range obstacles[sy] {
+			obstacles[sy][sx] = cam.sampleBlock(sx, sy, m.materials.Render)
 		}
 	}
 
-	// Convert grid to strings
-	for _, row := range grid {
-		lines = append(lines, strings.Join(row, ""))
+	// Place entities on the grid using animated display positions
+	selectedID := ""
+	if m.inspector.Open {
+		selectedID = m.inspector.SelectedID
+	}
+	if m.renderMode != RenderGlyphs {
+		// High-resolution modes draw entities as dots, several to a cell
+		drawEntityDots(comp.Layer(LayerEntities), cam, m.renderMode, sortByZOrder(m.entityManager.GetEntities()), selectedID)
+	} else {
+		drawEntityGlyphs(comp.Layer(LayerEntities), cam, m.entityManager.GetEntities(), selectedID, m.overlapPolicy)
+	}
+
+	// Sparks fly over empty space
+	drawSparks(comp, cam, m.effects.Sparks)
+
+	// Show the keyboard brush cursor while painting
+	overlay := comp.Layer(LayerOverlay)
+	if m.materialBrush > 0 && m.materials.Get(m.brushX, m.brushY) == MaterialEmpty {
+		cam.plotCell(overlay, m.brushX, m.brushY, Cell{Glyph: "+", Color: lipgloss.Color("#888888")})
+	}
+
+	// Draw the predicted path and the rubber band while aiming
+	if m.slingshot.Aiming {
+		pathColor := m.getSelectedColor()
+		for _, point := range m.predictLaunch() {
+			cam.plot(overlay, point[0], point[1], Cell{Glyph: "·", Color: pathColor})
+		}
+		for _, cell := range m.slingshot.BandCells() {
+			cam.plotCell(overlay, cell[0], cell[1], Cell{Glyph: "•", Color: lipgloss.Color("#C08040")})
+		}
 	}
 
-	// Enhanced physics info with better styling
+	frame := Frame{Width: contentWidth, Title: titleText, Performance: m.performanceMode, Grid: comp}
+
+	// Physics and settings info, styled by the renderer
 	gravity := m.physicsEngine.GetGravity()
 	bounce := m.physicsEngine.GetRestitution()
 
 	if m.performanceMode {
-		// Show performance metrics with special styling
-		physicsInfo := fmt.Sprintf("⚙️ Gravity: %.1f | 🏀 Bounce: %.2f | 📊 FPS: %.1f | 🎯 Limit: %d",
-			gravity, bounce, m.currentFPS, m.maxEntityLimit)
-		lines = append(lines, performanceModeStyle.Render(physicsInfo))
+		// Show performance metrics
+		physicsInfo := fmt.Sprintf("⚙️ Gravity: %.1f %s | 🏀 Bounce: %.2f | 📊 FPS: %.1f | 🎯 Limit: %d",
+			gravity, m.physicsEngine.GravityArrow(), bounce, m.currentFPS, m.maxEntityLimit)
 
 		// Add responsive layout debug info in performance mode
 		debugInfo := fmt.Sprintf("📐 Terminal: %dx%d | Sim: %dx%d | Ctrl: %dx%d",
 			m.termWidth, m.termHeight, m.simWidth, m.simHeight, m.ctrlWidth, m.ctrlHeight)
-		lines = append(lines, statusStyle.Render(debugInfo))
+		frame.Info = []string{physicsInfo, debugInfo}
 	} else {
-		// Standard physics info with enhanced styling
-		physicsInfo := fmt.Sprintf("⚙️ Gravity: %.1f | 🏀 Bounce: %.2f", gravity, bounce)
-		lines = append(lines, physicsInfoStyle.Render(physicsInfo))
+		// Standard physics info
+		physicsInfo := fmt.Sprintf("⚙️ Gravity: %.1f %s | 🏀 Bounce: %.2f", gravity, m.physicsEngine.GravityArrow(), bounce)
+		if m.tiltMode {
+			physicsInfo += fmt.Sprintf(" | 🧭 Tilt: %.0f°", m.physicsEngine.GetGravityAngle())
+		}
+		if m.slingshot.Aiming {
+			vx, vy := m.slingshot.LaunchVelocity()
+			physicsInfo += fmt.Sprintf(" | 🏹 Launch %s: %.1f", m.selectedEntityType, math.Hypot(vx, vy))
+		} else if m.selectedEntityType != SphereType {
+			physicsInfo += fmt.Sprintf(" | 🏹 Launch: %s", m.selectedEntityType)
+		}
+		if name := m.selectedBehaviorName(); name != "" {
+			physicsInfo += fmt.Sprintf(" | 🧠 Behavior: %s", name)
+		}
+		if m.materialBrush > 0 {
+			physicsInfo += fmt.Sprintf(" | 🖌 Brush: %s", materialBrushes[m.materialBrush].Name)
+		}
+		if m.prefabKind != PrefabPyramid {
+			physicsInfo += fmt.Sprintf(" | 🧱 Prefab: %s", m.prefabKind)
+		}
+		if !m.effects.Enabled {
+			physicsInfo += " | ✨ Effects off"
+		}
+		if m.overlapPolicy != OverlapTopmost && m.renderMode == RenderGlyphs {
+			physicsInfo += fmt.Sprintf(" | 🗂 Overlap: %s", m.overlapPolicy)
+		}
+		if m.renderMode != RenderGlyphs {
+			physicsInfo += fmt.Sprintf(" | 🔬 Render: %s", m.renderMode)
+		}
+		if m.camera.moved() || m.worldWidth > 0 {
+			physicsInfo += fmt.Sprintf(" | 🎥 Camera %s", m.camera)
+		}
+		if m.spawnGhosts {
+			physicsInfo += " | 👻 Ghosts"
+		}
+		if m.trailMode != TrailsOff {
+			physicsInfo += fmt.Sprintf(" | ☄ Trails: %s %d", m.trailMode, trailLengths[m.trailLengthIndex])
+		}
+		if len(m.sensors.Sensors()) > 0 {
+			physicsInfo += fmt.Sprintf(" | 🎯 Zone: %s", sensorPresets[m.sensorPreset].Name)
+		}
+		frame.Info = []string{physicsInfo}
 	}
 
-	// Enhanced status line with better visual organization
+	// Status line parts, laid out by the renderer to fit the width
 	totalEntities := m.entityManager.Count()
-	sphereCount := m.entityManager.CountByType(SphereType)
-	spriteCount := m.entityManager.CountByType(SpriteType)
 
 	// Create entity count display with expected format
 	var entityInfo string
@@ -786,11 +1041,11 @@ func (m Model) renderSimulation() string {
 		entityInfo = fmt.Sprintf("Entities: %d", totalEntities)
 	}
 
-	// Create type breakdown
-	typeInfo := fmt.Sprintf("● %d spheres | ◆ %d sprites", sphereCount, spriteCount)
-
-	// Create FPS display (always visible)
-	fpsInfo := fmt.Sprintf("FPS: %.1f", m.currentFPS)
+	// Create type breakdown from the entity type registry
+	var typeCounts []string
+	for _, info := range RegisteredEntityTypes() {
+		typeCounts = append(typeCounts, fmt.Sprintf("%s %d %s", info.Icon, m.entityManager.CountByType(info.Type), info.Plural))
+	}
 
 	// Create status indicator
 	statusIcon := "▶️"
@@ -799,65 +1054,19 @@ func (m Model) renderSimulation() string {
 		statusIcon = "⏸️"
 		statusText = "PAUSED"
 	}
-
-	// Combine status elements with enhanced styling
-	entityDisplay := entityCountStyle.Render(entityInfo)
-	typeDisplay := statusStyle.Render(typeInfo)
-	fpsDisplay := statusStyle.Render(fpsInfo)
-	statusDisplay := statusStyle.Render(fmt.Sprintf("%s %s", statusIcon, statusText))
-
-	// Create responsive status line based on available width
-	var statusLine string
-	if contentWidth < 20 {
-		// Ultra minimal: just entity count and FPS (essential info)
-		statusLine = fmt.Sprintf("%s FPS: %.1f", entityInfo, m.currentFPS)
-	} else if contentWidth < 30 {
-		// Ultra compact: entity count, FPS, and status
-		statusLine = lipgloss.JoinHorizontal(lipgloss.Left,
-			entityDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			fpsDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			statusDisplay,
-		)
-	} else if contentWidth < 40 {
-		// Compact: entity count, FPS, and status
-		statusLine = lipgloss.JoinHorizontal(lipgloss.Left,
-			entityDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			fpsDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			statusDisplay,
-		)
-	} else {
-		// Full status line for larger screens
-		statusLine = lipgloss.JoinHorizontal(lipgloss.Left,
-			entityDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			typeDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			fpsDisplay,
-			lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ "),
-			statusDisplay,
-		)
-	}
-
-	// Smart truncation - preserve essential information (Entities and FPS)
-	statusLineLength := len([]rune(statusLine))
-	if statusLineLength > contentWidth {
-		// If full status line is too long, fall back to essential info
-		essentialStatus := fmt.Sprintf("Entities: %d FPS: %.1f", totalEntities, m.currentFPS)
-		if len([]rune(essentialStatus)) <= contentWidth {
-			statusLine = essentialStatus
-		} else {
-			// Last resort: truncate but ensure it's valid
-			statusLine = string([]rune(statusLine)[:max(1, contentWidth-3)]) + "..."
-		}
+	state := fmt.Sprintf("%s %s", statusIcon, statusText)
+	if len(m.sensors.Sensors()) > 0 || m.score > 0 {
+		state = fmt.Sprintf("%s %s │ 🏆 Score: %d", statusIcon, statusText, m.score)
 	}
 
-	lines = append(lines, statusLine)
-
-	return strings.Join(lines, "\n")
+	frame.Status = StatusBar{
+		EntityCount: totalEntities,
+		Entities:    entityInfo,
+		Types:       strings.Join(typeCounts, " | "),
+		FPS:         m.currentFPS,
+		State:       state,
+	}
+	return frame
 }
 
 // renderMinimalSimulation creates ultra-simple simulation content for very small terminals
@@ -879,12 +1088,12 @@ func (m Model) renderMinimalSimulation() string {
 
 		// Place entities simply with proper bounds checking
 		for _, entity := range m.entityManager.GetEntities() {
-			x, y := entity.GetDisplayPosition()
-			if int(y) == i && int(x) >= 0 && int(x) < maxWidth {
+			x, y := m.camera.ToScreen(entity.GetDisplayPosition())
+			if y == i && x >= 0 && x < maxWidth {
 				runes := []rune(line)
 				// Double-check bounds for rune slice (defensive programming)
-				if int(x) < len(runes) {
-					runes[int(x)] = '●'
+				if x < len(runes) {
+					runes[x] = '●'
 					line = string(runes)
 				}
 			}
@@ -1029,7 +1238,7 @@ func (m *Model) handleBoundaryResize(maxWidth, maxHeight float64) {
 		
 		// Apply changes if needed
 		if newX != x || newY != y {
-			entity.SetImmediatePosition(newX, newY)
+			teleport(entity, newX, newY)
 		}
 		if newVX != vx || newVY != vy {
 			entity.SetVelocity(newVX, newVY)
@@ -1050,7 +1259,7 @@ func (m *Model) clampEntitiesToBounds(maxWidth, maxHeight float64) {
 		
 		// Only update if position changed to avoid unnecessary operations
 		if x != clampedX || y != clampedY {
-			entity.SetImmediatePosition(clampedX, clampedY)
+			teleport(entity, clampedX, clampedY)
 		}
 	}
 }
@@ -1080,8 +1289,8 @@ func stripANSISequences(input string) string {
 // Parameter management functions
 var gravityLevels = []float64{0.0, 10.0, 25.0, 50.0}
 var gravityNames = []string{"Zero", "Low", "Normal", "High"}
-var entitySizes = []int{1, 2, 3, 4}
-var entitySizeNames = []string{"Tiny", "Small", "Medium", "Large"}
+var entitySizes = []int{1, 2, 3, 4, 5, 6}
+var entitySizeNames = []string{"Tiny", "Small", "Medium", "Large", "Huge", "Giant"}
 
 // GetAvailableColors returns enhanced vibrant colors for better visual appeal
 func GetAvailableColors() []lipgloss.Color {
@@ -1110,6 +1319,27 @@ var colorNames = []string{
 	"Tomato", "Turquoise", "Sky Blue", "Pale Green", "Orange", "Orchid", "Light Sea Green", "Light Pink", "Green Yellow",
 }
 
+// tiltStep is how far one arrow press rotates gravity in tilt mode, in degrees
+const tiltStep = 15.0
+
+// tiltGravity rotates gravity for an arrow key and reports whether the key was used.
+// Left/right tilt the box so things slide that way, up turns it upside down and down rights it.
+func (m *Model) tiltGravity(key string) bool {
+	switch key {
+	case "left":
+		m.physicsEngine.RotateGravity(tiltStep)
+	case "right":
+		m.physicsEngine.RotateGravity(-tiltStep)
+	case "up":
+		m.physicsEngine.RotateGravity(180)
+	case "down":
+		m.physicsEngine.SetGravityAngle(90)
+	default:
+		return false
+	}
+	return true
+}
+
 // Parameter cycling functions
 func (m *Model) cycleGravity() {
 	for i, gravity := range gravityLevels {
@@ -1145,39 +1375,285 @@ func (m *Model) getSelectedColor() lipgloss.Color {
 	return colors[m.selectedColorIndex]
 }
 
-// runStressTest adds multiple entities quickly for performance testing
-func (m *Model) runStressTest() {
-	if m.simWidth <= 0 || m.simHeight <= 0 {
-		return // Can't add entities if dimensions aren't set
+func (m *Model) cycleBehavior() {
+	if len(m.behaviorNames) == 0 {
+		return
 	}
+	m.selectedBehavior = (m.selectedBehavior + 1) % len(m.behaviorNames)
+}
 
-	// Add entities (mix of spheres and sprites) rapidly, respecting limit
-	entitiesAdded := 0
-	for i := 0; i < StressTestEntities; i++ {
-		// Check limit before adding each entity
-		if m.entityManager.Count() >= m.maxEntityLimit {
-			break
+// selectedBehaviorName returns the behavior for new entities, or "" for none
+func (m Model) selectedBehaviorName() string {
+	if m.selectedBehavior < 0 || m.selectedBehavior >= len(m.behaviorNames) {
+		return ""
+	}
+	return m.behaviorNames[m.selectedBehavior]
+}
+
+// applySpawnSettings applies the per-spawn toggles (ghost layer, behavior) to a new entity
+func (m *Model) applySpawnSettings(entity Entity) {
+	if m.spawnGhosts {
+		if ghost, ok := entity.(interface{ MakeGhost() }); ok {
+			ghost.MakeGhost()
+		}
+	}
+	m.attachSelectedBehavior(entity)
+}
+
+// attachSelectedBehavior builds a fresh instance of the selected behavior for entity
+func (m *Model) attachSelectedBehavior(entity Entity) {
+	spec, ok := m.behaviorSpecs[m.selectedBehaviorName()]
+	if !ok {
+		return
+	}
+	host, ok := entity.(interface{ SetBehavior(Behavior) })
+	if !ok {
+		return
+	}
+	pe := m.physicsEngine
+	behavior, err := spec.Build(pe.MinX, pe.MinY, pe.MaxX, pe.MaxY)
+	if err != nil {
+		return // Specs are validated on load, so this only guards against bad built-ins
+	}
+	host.SetBehavior(behavior)
+}
+
+// cycleSpringPreset switches every entity's animation to the next spring preset
+func (m *Model) cycleSpringPreset() {
+	m.springPreset = (m.springPreset + 1) % len(springPresets)
+	preset := springPresets[m.springPreset]
+	m.animationEngine.ApplyPreset(preset)
+	m.controlPanel.UpdateSpringsButton(preset.Name)
+}
+
+// applyTrailLength tells the animation engine how many positions to record for trails
+func (m *Model) applyTrailLength() {
+	m.animationEngine.TrailLength = 0
+	if m.trailMode != TrailsOff {
+		m.animationEngine.TrailLength = trailLengths[m.trailLengthIndex]
+	}
+}
+
+// addBehaviorSpecs makes loaded behaviors available for cycling, replacing built-ins with the same name
+func (m *Model) addBehaviorSpecs(specs map[string]BehaviorSpec) {
+	for _, name := range sortedBehaviorNames(specs) {
+		if _, exists := m.behaviorSpecs[name]; !exists {
+			m.behaviorNames = append(m.behaviorNames, name)
 		}
+		m.behaviorSpecs[name] = specs[name]
+	}
+}
 
-		x := float64(rand.Intn(m.simWidth-4) + 2)  // Keep away from borders
-		y := float64(2 + rand.Intn(m.simHeight-6)) // Spread vertically
-		size := rand.Intn(4) + 1                   // Random size 1-4
-		color := GetRandomColor()                  // Random color
+// handleSensorEvents applies the entry actions of trigger zones
+func (m *Model) handleSensorEvents(events []SensorEvent) {
+	for _, event := range events {
+		if event.Kind != SensorEnter {
+			continue
+		}
+		actions := event.Sensor.OnEnter
+		if actions.Recolor != "" {
+			if recolorable, ok := event.Entity.(interface{ SetColor(lipgloss.Color) }); ok {
+				recolorable.SetColor(actions.Recolor)
+			}
+		}
+		if actions.Teleport {
+			teleport(event.Entity, actions.TeleportX, actions.TeleportY)
+		}
+		if actions.Remove {
+			m.entityManager.RemoveEntity(event.Entity.GetID())
+		}
+		m.score += actions.Score
+	}
+}
 
-		var entity Entity
-		if rand.Float64() < 0.5 {
-			// Add sphere
-			entity = NewSphere(x, y, size, color)
-		} else {
-			// Add sprite
-			entity = NewSprite(x, y, size, color, "")
+// placeSensor adds a zone of the selected preset centered on the given grid cell.
+// Portals send entities back to the top middle of the simulation.
+func (m *Model) placeSensor(x, y int) {
+	const zoneWidth, zoneHeight = 6, 3
+	portalX := (m.physicsEngine.MinX + m.physicsEngine.MaxX) / 2
+	portalY := m.physicsEngine.MinY + 1
+	m.sensors.Add(newPresetSensor(m.sensorPreset,
+		float64(x-zoneWidth/2), float64(y-zoneHeight/2), zoneWidth, zoneHeight, portalX, portalY))
+}
+
+// cycleEntityType selects the next registered entity type for the slingshot
+func (m *Model) cycleEntityType() {
+	types := RegisteredEntityTypes()
+	for i, info := range types {
+		if info.Type == m.selectedEntityType {
+			m.selectedEntityType = types[(i+1)%len(types)].Type
+			return
 		}
+	}
+	m.selectedEntityType = types[0].Type
+}
+
+// newSelectedEntity creates an entity of the selected type, size and color at (x, y)
+func (m *Model) newSelectedEntity(x, y float64) Entity {
+	return m.newEntity(m.selectedEntityType, x, y)
+}
 
-		// Add random velocity for immediate action
+// newEntity creates an entity of a registered type with the selected size and color,
+// falling back to a sphere for unknown types
+func (m *Model) newEntity(entityType EntityType, x, y float64) Entity {
+	size := m.selectedEntitySize
+	color := m.getSelectedColor()
+	if entity, ok := NewEntityOfType(entityType, x, y, size, color); ok {
+		return entity
+	}
+	return NewSphere(x, y, size, color)
+}
+
+// spawnEntity adds an entity of the given type near the top with the selected parameters
+func (m *Model) spawnEntity(entityType EntityType) {
+	if m.entityManager.Count() >= m.maxEntityLimit { // Dynamic entity limit
+		return
+	}
+
+	worldWidth, _ := m.worldSize()
+	x := float64(rand.Intn(max(1, worldWidth-4)) + 2) // Keep away from borders
+	y := float64(2 + rand.Intn(3))            // Start near top
+	entity := m.newEntity(entityType, x, y)
+	m.applySpawnSettings(entity)
+
+	// Add some initial random velocity for more interesting physics;
+	// self-propelled entities set their own velocity
+	if exempt, ok := entity.(gravityExempt); !ok || !exempt.IgnoresGravity() {
+		m.physicsEngine.AddRandomVelocity(entity, 5.0)
+	}
+
+	m.entityManager.AddEntity(entity)
+}
+
+// handleSlingshot aims and launches entities with the mouse and reports whether it used the event.
+// A left press in the simulation anchors the shot, dragging pulls the band back and
+// releasing launches a new entity from the anchor in the opposite direction.
+func (m *Model) handleSlingshot(msg tea.MouseMsg) bool {
+	switch {
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		if !m.cursorInSim || m.materialBrush > 0 {
+			return false
+		}
+		m.slingshot.Begin(m.cursorX, m.cursorY)
+		return true
+	case !m.slingshot.Aiming:
+		return false
+	case msg.Action == tea.MouseActionMotion:
+		if m.cursorInSim {
+			m.slingshot.Drag(m.cursorX, m.cursorY)
+		}
+		return true
+	case msg.Action == tea.MouseActionRelease:
+		if m.cursorInSim {
+			m.slingshot.Drag(m.cursorX, m.cursorY)
+		}
+		m.launchEntity()
+		return true
+	}
+	return false
+}
+
+// launchEntity spawns the selected entity at the slingshot anchor with the launch velocity
+func (m *Model) launchEntity() {
+	m.slingshot.Cancel()
+	if m.entityManager.Count() >= m.maxEntityLimit {
+		return
+	}
+
+	entity := m.newSelectedEntity(m.slingshot.AnchorX, m.slingshot.AnchorY)
+	m.applySpawnSettings(entity)
+	entity.SetVelocity(m.slingshot.LaunchVelocity())
+	m.entityManager.AddEntity(entity)
+}
+
+// predictLaunch returns the path a launched entity would take if released now
+func (m Model) predictLaunch() [][2]float64 {
+	probe := m.newSelectedEntity(m.slingshot.AnchorX, m.slingshot.AnchorY)
+	if m.spawnGhosts {
+		if ghost, ok := probe.(interface{ MakeGhost() }); ok {
+			ghost.MakeGhost()
+		}
+	}
+	probe.SetVelocity(m.slingshot.LaunchVelocity())
+	return m.physicsEngine.PredictTrajectory(probe, trajectoryPreviewSteps)
+}
+
+// paintMaterial paints the selected brush material around the given grid cell
+func (m *Model) paintMaterial(x, y int) {
+	if m.materialBrush <= 0 || m.materialBrush >= len(materialBrushes) {
+		return
+	}
+	m.materials.Paint(x, y, 1, materialBrushes[m.materialBrush].Material)
+}
+
+// moveBrush moves the keyboard brush cursor one cell, staying on the grid
+func (m *Model) moveBrush(key string) {
+	switch key {
+	case "shift+left":
+		m.brushX--
+	case "shift+right":
+		m.brushX++
+	case "shift+up":
+		m.brushY--
+	case "shift+down":
+		m.brushY++
+	}
+	m.brushX = max(0, min(m.brushX, m.materials.Width-1))
+	m.brushY = max(0, min(m.brushY, m.materials.Height-1))
+}
+
+// world returns the query view of the simulation handed to behaviors
+func (m Model) world() World {
+	return &simulationWorld{
+		entityManager: m.entityManager,
+		physicsEngine: m.physicsEngine,
+		cursorX:       m.cursorX,
+		cursorY:       m.cursorY,
+		hasCursor:     m.cursorInSim,
+	}
+}
+
+// screenToSim converts terminal cell coordinates to simulation coordinates through the camera.
+// The result is the center of the grid cell; ok is false outside the grid.
+func (m Model) screenToSim(screenX, screenY int) (x, y float64, ok bool) {
+	originX, originY := SimGridOriginX, SimGridOriginY
+	gridWidth, gridHeight := m.simGridSize() // The grid renderSimulation draws
+	if m.termWidth <= UltraCompactWidth {
+		// Minimal layout: title line, then the grid at column 0
+		originX, originY = 0, 1
+		gridWidth, gridHeight = max(5, m.termWidth-5), max(1, m.termHeight-8)
+	}
+
+	gridX := screenX - originX
+	gridY := screenY - originY
+	if gridX < 0 || gridY < 0 || gridX >= gridWidth || gridY >= gridHeight {
+		return 0, 0, false
+	}
+	x, y = m.camera.ToWorld(gridX, gridY)
+	return x, y, true
+}
+
+// runStressTest adds multiple entities quickly for performance testing
+func (m *Model) runStressTest() {
+	if m.simWidth <= 0 || m.simHeight <= 0 {
+		return // Can't add entities if dimensions aren't set
+	}
+
+	// Scatter a non-overlapping cloud of spheres and sprites in the middle, respecting limit
+	pe := m.physicsEngine
+	params := defaultPrefabParams(PrefabCloud)
+	params.Count = StressTestEntities
+	params.Size = rand.Intn(4) + 1 // Random size 1-4
+	params.Types = []EntityType{SphereType, SpriteType}
+	params.From, params.To = GetRandomColor(), GetRandomColor()
+	params.Area = [4]float64{pe.MinX, pe.MinY, pe.MaxX, pe.MaxY}
+	cloud := BuildPrefab(PrefabCloud, (pe.MinX+pe.MaxX)/2, (pe.MinY+pe.MaxY)/2, params)
+
+	// Add random velocity for immediate action
+	for _, entity := range cloud {
 		m.physicsEngine.AddRandomVelocity(entity, 10.0)
-		m.entityManager.AddEntity(entity)
-		entitiesAdded++
 	}
+	entitiesAdded := m.addPrefab(cloud)
 
 	// Enable performance mode automatically during stress test
 	if entitiesAdded > 0 {
@@ -1187,10 +1663,56 @@ func (m *Model) runStressTest() {
 }
 
 func main() {
+	behaviorFile := flag.String("behaviors", "", "JSON file with extra behaviors to cycle with the v key")
+	spriteFile := flag.String("sprites", "", "JSON sprite sheet with animated sprites to add as entity types")
+	worldSize := flag.String("world", "", "fixed world size such as 240x80; by default the world fills the window")
+	renderMode := flag.String("render", "glyphs", "render mode: glyphs, halfblocks or braille")
+	plain := flag.Bool("plain", false, "draw the simulation pane in plain ASCII with no colors")
+	flag.Parse()
+
+	// Sprite sheet types must be registered before the control panel builds its buttons
+	if *spriteFile != "" {
+		sheet, err := LoadSpriteSheetFile(*spriteFile)
+		if err == nil {
+			err = RegisterSpriteSheet(sheet)
+		}
+		if err != nil {
+			fmt.Printf("Error loading sprites: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	model := initialModel()
+	if *behaviorFile != "" {
+		specs, err := LoadBehaviorFile(*behaviorFile)
+		if err != nil {
+			fmt.Printf("Error loading behaviors: %v\n", err)
+			os.Exit(1)
+		}
+		model.addBehaviorSpecs(specs)
+	}
+	if *worldSize != "" {
+		width, height, err := parseWorldSize(*worldSize)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		model.SetWorldSize(width, height)
+	}
+	mode, err := parseRenderMode(*renderMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	model.setRenderMode(mode)
+	if *plain {
+		model.renderer = PlainRenderer{}
+	}
+
 	p := tea.NewProgram(
-		initialModel(),
+		model,
 		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
+		tea.WithMouseAllMotion(), // Report hover so behaviors can follow the cursor
 	)
 
 	if _, err := p.Run(); err != nil {
@@ -3,12 +3,14 @@ package main
 import (
 	"math"
 	"math/rand"
+	"sort"
 )
 
 // PhysicsEngine handles all physics calculations and simulations
 type PhysicsEngine struct {
 	// Physics constants
-	Gravity        float64 // Gravity acceleration (pixels/second²)
+	Gravity        float64 // Gravity acceleration magnitude (pixels/second²)
+	GravityAngle   float64 // Direction gravity pulls in degrees; 90 is straight down (screen Y grows downward)
 	AirResistance  float64 // Air resistance coefficient (0-1)
 	Restitution    float64 // Bounce factor for collisions (0-1)
 	StaticFriction float64 // Static friction when entities are nearly at rest
@@ -27,8 +29,24 @@ type PhysicsEngine struct {
 
 	// Collision precision
 	ContactTolerance float64 // How close entities can get before being considered touching
+
+	// Optional falling-sand layer whose solid cells act as obstacles
+	Materials *MaterialGrid
+
+	// Collision layers for the static world
+	WallCategory, WallMask         CollisionLayer
+	ObstacleCategory, ObstacleMask CollisionLayer
+
+	// Receives collision, wall hit and sleep events; usually shared with the EntityManager
+	Lifecycle *Lifecycle
+
+	// Entities that came to rest on the last step; they wake when they move again
+	asleep map[Entity]bool
 }
 
+// sleepDistance is how far an entity may move in a step and still count as resting
+const sleepDistance = 0.01
+
 // NewPhysicsEngine creates a new physics engine with default settings
 func NewPhysicsEngine(boundsWidth, boundsHeight float64) *PhysicsEngine {
 	// Validate and sanitize dimensions
@@ -41,6 +59,7 @@ func NewPhysicsEngine(boundsWidth, boundsHeight float64) *PhysicsEngine {
 
 	return &PhysicsEngine{
 		Gravity:          25.0, // Reasonable gravity for terminal display
+		GravityAngle:     90.0, // Straight down
 		AirResistance:    0.05, // Increased air resistance for better settling
 		Restitution:      0.7,  // Bouncy but not perfectly elastic
 		StaticFriction:   0.8,  // Strong static friction to prevent jittering
@@ -53,6 +72,10 @@ func NewPhysicsEngine(boundsWidth, boundsHeight float64) *PhysicsEngine {
 		MaxVelocity:      50.0, // Cap velocity for visual reasons
 		MinVelocity:      0.05, // Lower threshold for stopping
 		ContactTolerance: 0.1,  // Allow entities to touch more closely
+		WallCategory:     LayerWall,
+		WallMask:         LayerAll,
+		ObstacleCategory: LayerObstacle,
+		ObstacleMask:     LayerAll,
 	}
 }
 
@@ -64,19 +87,57 @@ func (pe *PhysicsEngine) UpdateBounds(width, height float64) {
 
 // ApplyPhysics applies all physics calculations to entities
 func (pe *PhysicsEngine) ApplyPhysics(entities []Entity) {
+	// Self-propelled entities pick their velocity before forces are applied
+	pe.applyFlocking(entities)
+
+	asleep := make(map[Entity]bool, len(pe.asleep))
 	for _, entity := range entities {
+		// Frozen entities ignore forces and hold their position
+		if isFrozen(entity) {
+			entity.SetVelocity(0, 0)
+			continue
+		}
+		wasAsleep := pe.asleep[entity]
+		startX, startY := entity.GetPosition()
+
 		pe.applyGravity(entity)
 		pe.applyAirResistance(entity)
 		pe.updatePosition(entity)
-		pe.handleBoundaryCollisions(entity)
+		edges := pe.handleBoundaryCollisions(entity)
+		pe.handleMaterialCollisions(entity)
 		pe.capVelocity(entity)
+
+		// Resting entities press against the floor every step; only moving ones hit walls
+		if !wasAsleep {
+			for _, edge := range edges {
+				pe.Lifecycle.hitWall(entity, edge)
+			}
+		}
+		x, y := entity.GetPosition()
+		if math.Hypot(x-startX, y-startY) < sleepDistance {
+			asleep[entity] = true
+			if !wasAsleep {
+				pe.Lifecycle.slept(entity)
+			}
+		}
 	}
+	pe.asleep = asleep
+}
+
+// gravityExempt is implemented by entities that propel themselves and ignore gravity
+type gravityExempt interface {
+	IgnoresGravity() bool
 }
 
-// applyGravity applies downward gravitational force
+// applyGravity applies gravitational force along the gravity vector
 func (pe *PhysicsEngine) applyGravity(entity Entity) {
+	if exempt, ok := entity.(gravityExempt); ok && exempt.IgnoresGravity() {
+		return
+	}
+
 	// Apply gravity force: F = mg (simplified to just g since mass is in the ApplyForce method)
-	entity.ApplyForce(0, pe.Gravity*pe.DeltaTime)
+	gx, gy := pe.GravityVector()
+	entity.ApplyForce(gx*pe.DeltaTime, gy*pe.DeltaTime)
 }
 
 // applyAirResistance applies air resistance to slow down entities
@@ -95,45 +156,56 @@ func (pe *PhysicsEngine) updatePosition(entity Entity) {
 	entity.Update(pe.DeltaTime)
 }
 
-// handleBoundaryCollisions keeps entities within the simulation bounds
-func (pe *PhysicsEngine) handleBoundaryCollisions(entity Entity) {
+// handleBoundaryCollisions keeps entities within the simulation bounds and returns the walls hit
+func (pe *PhysicsEngine) handleBoundaryCollisions(entity Entity) (edges []WallEdge) {
+	if !pe.collidesWithWalls(entity) {
+		return nil
+	}
+
 	x, y := entity.GetPosition()
 	vx, vy := entity.GetVelocity()
-	size := float64(entity.GetSize())
+	halfWidth, halfHeight := boundaryExtents(entity)
 
 	// Calculate entity bounds
-	entityMinX := x - size/2
-	entityMaxX := x + size/2
-	entityMinY := y - size/2
-	entityMaxY := y + size/2
+	entityMinX := x - halfWidth
+	entityMaxX := x + halfWidth
+	entityMinY := y - halfHeight
+	entityMaxY := y + halfHeight
 
 	// Horizontal boundary collisions
 	if entityMinX <= pe.MinX {
 		// Hit left wall
-		newX := pe.MinX + size/2
+		newX := pe.MinX + halfWidth
+		vx = -vx * pe.Restitution
 		entity.SetImmediatePosition(newX, y) // Immediate position for crisp bounce
-		entity.SetVelocity(-vx*pe.Restitution, vy)
-		x = newX // Update position variable for subsequent collisions
+		entity.SetVelocity(vx, vy)
+		x = newX // Update position and velocity for subsequent collisions
+		edges = append(edges, WallLeft)
 	} else if entityMaxX >= pe.MaxX {
 		// Hit right wall
-		newX := pe.MaxX - size/2
+		newX := pe.MaxX - halfWidth
+		vx = -vx * pe.Restitution
 		entity.SetImmediatePosition(newX, y) // Immediate position for crisp bounce
-		entity.SetVelocity(-vx*pe.Restitution, vy)
-		x = newX // Update position variable for subsequent collisions
+		entity.SetVelocity(vx, vy)
+		x = newX // Update position and velocity for subsequent collisions
+		edges = append(edges, WallRight)
 	}
 
 	// Vertical boundary collisions
 	if entityMinY <= pe.MinY {
 		// Hit top wall
-		newY := pe.MinY + size/2
+		newY := pe.MinY + halfHeight
 		entity.SetImmediatePosition(x, newY) // Use updated x position
 		entity.SetVelocity(vx, -vy*pe.Restitution)
+		edges = append(edges, WallTop)
 	} else if entityMaxY >= pe.MaxY {
 		// Hit bottom wall
-		newY := pe.MaxY - size/2
+		newY := pe.MaxY - halfHeight
 		entity.SetImmediatePosition(x, newY) // Immediate position for crisp bounce
 		entity.SetVelocity(vx, -vy*pe.Restitution)
+		edges = append(edges, WallBottom)
 	}
+	return edges
 }
 
 // capVelocity ensures velocities don't become too extreme
@@ -179,12 +251,33 @@ func (pe *PhysicsEngine) HandleEntityCollisions(entities []Entity) {
 	}
 }
 
-// findCollisions detects all entity-to-entity collisions
+// findCollisions detects all entity-to-entity collisions. A spatial hash limits the
+// narrow-phase test to entities whose bounds are near each other; pairs are reported
+// in slice order.
 func (pe *PhysicsEngine) findCollisions(entities []Entity) []CollisionPair {
 	var collisions []CollisionPair
 
-	for i := 0; i < len(entities); i++ {
-		for j := i + 1; j < len(entities); j++ {
+	index := newSpatialHash(entities)
+	order := make(map[Entity]int, len(entities))
+	for i, entity := range entities {
+		order[entity] = i
+	}
+
+	for i, entity := range entities {
+		x, y, w, h := entity.GetBounds()
+		var later []int
+		for _, other := range queryRect(index, x, y, x+w, y+h) {
+			if j := order[other]; j > i {
+				later = append(later, j)
+			}
+		}
+		sort.Ints(later)
+
+		for _, j := range later {
+			// Cheap layer check first so filtered pairs skip the distance test
+			if !shouldCollide(entities[i], entities[j]) {
+				continue
+			}
 			if pe.checkEntityCollision(entities[i], entities[j]) {
 				collisions = append(collisions, CollisionPair{
 					Entity1: entities[i],
@@ -202,22 +295,18 @@ func (pe *PhysicsEngine) checkEntityCollision(e1, e2 Entity) bool {
 	x1, y1 := e1.GetPosition()
 	x2, y2 := e2.GetPosition()
 
-	// Use GetBounds to get the actual collision sizes
-	_, _, w1, _ := e1.GetBounds()
-	_, _, w2, _ := e2.GetBounds()
-
-	// Calculate effective radii from bounds
-	radius1 := w1 / 2
-	radius2 := w2 / 2
-
 	// Calculate distance between centers
 	dx := x2 - x1
 	dy := y2 - y1
 	distance := math.Sqrt(dx*dx + dy*dy)
+	if distance == 0 {
+		return true
+	}
 
-	// Check if distance is less than sum of radii plus contact tolerance
-	// This allows entities to touch more closely
-	minDistance := (radius1 + radius2) - pe.ContactTolerance
+	// Check if distance is less than the sum of the shapes' extents toward each other
+	// plus contact tolerance. This allows entities to touch more closely
+	nx, ny := dx/distance, dy/distance
+	minDistance := (collisionExtent(e1, nx, ny) + collisionExtent(e2, nx, ny)) - pe.ContactTolerance
 	return distance < minDistance
 }
 
@@ -244,16 +333,8 @@ func (pe *PhysicsEngine) resolveCollision(e1, e2 Entity) {
 	nx := dx / distance
 	ny := dy / distance
 
-	// Separate entities if they're overlapping
-	// Use GetBounds to get the actual collision sizes
-	_, _, w1, _ := e1.GetBounds()
-	_, _, w2, _ := e2.GetBounds()
-
-	// Calculate effective radii from bounds
-	radius1 := w1 / 2
-	radius2 := w2 / 2
-
-	minDistance := (radius1 + radius2) - pe.ContactTolerance
+	// Separate entities if they're overlapping, using each shape's extent along the normal
+	minDistance := (collisionExtent(e1, nx, ny) + collisionExtent(e2, nx, ny)) - pe.ContactTolerance
 	overlap := minDistance - distance
 
 	if overlap > 0 {
@@ -262,8 +343,17 @@ func (pe *PhysicsEngine) resolveCollision(e1, e2 Entity) {
 		separationX := nx * overlap * separationFactor
 		separationY := ny * overlap * separationFactor
 
-		e1.SetImmediatePosition(x1-separationX, y1-separationY)
-		e2.SetImmediatePosition(x2+separationX, y2+separationY)
+		// Frozen entities are pinned, so the other one moves the whole way
+		switch {
+		case isFrozen(e1) && isFrozen(e2):
+		case isFrozen(e1):
+			e2.SetImmediatePosition(x2+2*separationX, y2+2*separationY)
+		case isFrozen(e2):
+			e1.SetImmediatePosition(x1-2*separationX, y1-2*separationY)
+		default:
+			e1.SetImmediatePosition(x1-separationX, y1-separationY)
+			e2.SetImmediatePosition(x2+separationX, y2+separationY)
+		}
 	}
 
 	// Calculate relative velocity in collision normal direction
@@ -303,6 +393,10 @@ func (pe *PhysicsEngine) resolveCollision(e1, e2 Entity) {
 	// Apply impulse to velocities
 	e1.SetVelocity(vx1+impulse*nx, vy1+impulse*ny)
 	e2.SetVelocity(vx2-impulse*nx, vy2-impulse*ny)
+
+	// Both entities hear about the impact, first then second
+	pe.Lifecycle.collided(e1, e2, math.Abs(impulse))
+	pe.Lifecycle.collided(e2, e1, math.Abs(impulse))
 }
 
 // AddRandomVelocity adds some initial random velocity to an entity
@@ -329,6 +423,86 @@ func (pe *PhysicsEngine) GetGravity() float64 {
 	return pe.Gravity
 }
 
+// GravityVector returns the gravity acceleration as x and y components
+func (pe *PhysicsEngine) GravityVector() (gx, gy float64) {
+	sin, cos := math.Sincos(pe.GravityAngle * math.Pi / 180)
+	// Snap floating-point noise so axis-aligned gravity has no sideways drift
+	if math.Abs(sin) < 1e-12 {
+		sin = 0
+	}
+	if math.Abs(cos) < 1e-12 {
+		cos = 0
+	}
+	return pe.Gravity * cos, pe.Gravity * sin
+}
+
+// SetGravityVector sets gravity from x and y components.
+// A zero vector keeps the current direction so tilting still works with gravity off.
+func (pe *PhysicsEngine) SetGravityVector(gx, gy float64) {
+	if math.IsInf(gx, 0) || math.IsNaN(gx) || math.IsInf(gy, 0) || math.IsNaN(gy) {
+		return // Reject invalid values
+	}
+	pe.Gravity = math.Hypot(gx, gy)
+	if pe.Gravity > 0 {
+		pe.SetGravityAngle(math.Atan2(gy, gx) * 180 / math.Pi)
+	}
+}
+
+// SetGravityAngle points gravity in the given direction in degrees (0 is right, 90 is down)
+func (pe *PhysicsEngine) SetGravityAngle(degrees float64) {
+	if math.IsInf(degrees, 0) || math.IsNaN(degrees) {
+		return // Reject invalid values
+	}
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	pe.GravityAngle = degrees
+	if pe.Materials != nil {
+		pe.Materials.SetDown(pe.GravityDirection())
+	}
+}
+
+// GetGravityAngle returns the direction of gravity in degrees
+func (pe *PhysicsEngine) GetGravityAngle() float64 {
+	return pe.GravityAngle
+}
+
+// RotateGravity turns gravity by the given number of degrees.
+// Positive values rotate clockwise on screen.
+func (pe *PhysicsEngine) RotateGravity(degrees float64) {
+	pe.SetGravityAngle(pe.GravityAngle + degrees)
+}
+
+// GravityDirection returns the grid axis closest to the gravity direction as a unit cell step.
+// Ties between axes favor the vertical one.
+func (pe *PhysicsEngine) GravityDirection() (dx, dy int) {
+	sin, cos := math.Sincos(pe.GravityAngle * math.Pi / 180)
+	if math.Abs(cos) > math.Abs(sin)+1e-9 {
+		if cos > 0 {
+			return 1, 0
+		}
+		return -1, 0
+	}
+	if sin < 0 {
+		return 0, -1
+	}
+	return 0, 1
+}
+
+// gravityArrows maps the eight compass directions to arrows, starting at 0° (right)
+// and turning clockwise on screen
+var gravityArrows = []string{"→", "↘", "↓", "↙", "←", "↖", "↑", "↗"}
+
+// GravityArrow returns an arrow pointing the way gravity pulls
+func (pe *PhysicsEngine) GravityArrow() string {
+	index := int(math.Round(pe.GravityAngle/45)) % len(gravityArrows)
+	if index < 0 {
+		index += len(gravityArrows)
+	}
+	return gravityArrows[index]
+}
+
 // SetRestitution allows dynamic bounce adjustment
 func (pe *PhysicsEngine) SetRestitution(restitution float64) {
 	if restitution >= 0 && restitution <= 1 {
@@ -403,3 +403,64 @@ func TestCompletePhysicsCycle(t *testing.T) {
 		t.Error("Sphere2 should have moved from initial position")
 	}
 }
+
+// Test gravity as a vector
+func TestGravityVector(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+
+	if gx, gy := pe.GravityVector(); gx != 0 || gy != 25.0 {
+		t.Errorf("Expected default gravity (0, 25), got (%.2f, %.2f)", gx, gy)
+	}
+	if pe.GravityArrow() != "↓" {
+		t.Errorf("Expected down arrow, got %s", pe.GravityArrow())
+	}
+
+	pe.SetGravityVector(-10, 0)
+	if pe.GetGravity() != 10 || pe.GetGravityAngle() != 180 {
+		t.Errorf("Expected gravity 10 at 180°, got %.1f at %.1f°", pe.GetGravity(), pe.GetGravityAngle())
+	}
+	if dx, dy := pe.GravityDirection(); dx != -1 || dy != 0 {
+		t.Errorf("Expected left grid direction, got (%d, %d)", dx, dy)
+	}
+
+	pe.RotateGravity(-270)
+	if pe.GetGravityAngle() != 270 || pe.GravityArrow() != "↑" {
+		t.Errorf("Expected gravity to wrap to 270° (up), got %.1f° %s", pe.GetGravityAngle(), pe.GravityArrow())
+	}
+
+	pe.SetGravityAngle(math.NaN())
+	if pe.GetGravityAngle() != 270 {
+		t.Error("Expected NaN angle to be rejected")
+	}
+}
+
+// Test sideways and upside-down gravity against the walls
+func TestTiltedGravityReachesWalls(t *testing.T) {
+	tests := []struct {
+		angle float64
+		check func(pe *PhysicsEngine, x, y float64) bool
+		wall  string
+	}{
+		{0, func(pe *PhysicsEngine, x, y float64) bool { return x >= pe.MaxX-1.5 }, "right"},
+		{180, func(pe *PhysicsEngine, x, y float64) bool { return x <= pe.MinX+1.5 }, "left"},
+		{270, func(pe *PhysicsEngine, x, y float64) bool { return y <= pe.MinY+1.5 }, "top"},
+	}
+
+	for _, tt := range tests {
+		pe := NewPhysicsEngine(40, 20)
+		pe.SetGravityAngle(tt.angle)
+		sphere := NewSphere(20.0, 10.0, 1, lipgloss.Color("32"))
+
+		for i := 0; i < 200; i++ {
+			pe.ApplyPhysics([]Entity{sphere})
+		}
+
+		x, y := sphere.GetPosition()
+		if !tt.check(pe, x, y) {
+			t.Errorf("Expected sphere to settle against the %s wall, got (%.2f, %.2f)", tt.wall, x, y)
+		}
+		if x-0.5 < pe.MinX || x+0.5 > pe.MaxX || y-0.5 < pe.MinY || y+0.5 > pe.MaxY {
+			t.Errorf("Sphere escaped the bounds with gravity toward the %s wall: (%.2f, %.2f)", tt.wall, x, y)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PrefabKind is an arranged group of entities spawned in one action
+type PrefabKind int
+
+const (
+	PrefabPyramid PrefabKind = iota // Stacked rows, each one shorter than the one below
+	PrefabLattice                   // Columns × rows grid
+	PrefabChain                     // Links hanging from a pinned top link
+	PrefabRing                      // Entities spaced evenly around a circle
+	PrefabCloud                     // Random scatter with no initial overlap
+)
+
+var prefabNames = []string{"pyramid", "lattice", "chain", "ring", "cloud"}
+
+// String returns the prefab's name
+func (kind PrefabKind) String() string {
+	return prefabNames[kind]
+}
+
+// PrefabParams shapes a prefab. Spacing is measured between entity footprints, so
+// neighbors never overlap whatever their size.
+type PrefabParams struct {
+	Count   int          // Pyramid base width, lattice columns, chain links, or ring and cloud members
+	Rows    int          // Lattice rows; the other prefabs ignore it
+	Spacing float64      // Empty cells between neighboring footprints
+	Size    int          // Size of every entity
+	Types   []EntityType // Cycled through entity by entity; empty means spheres
+	From    lipgloss.Color
+	To      lipgloss.Color // The first entity is From, the last To, with a blend in between
+
+	// World area, as MinX, MinY, MaxX, MaxY, that a cloud is scattered inside. The
+	// zero value leaves the cloud unbounded.
+	Area [4]float64
+}
+
+// defaultPrefabParams returns the starting parameters for each prefab kind
+func defaultPrefabParams(kind PrefabKind) PrefabParams {
+	params := PrefabParams{Count: 5, Size: 1, From: lipgloss.Color("#00FF7F"), To: lipgloss.Color("#1E90FF")}
+	switch kind {
+	case PrefabLattice:
+		params.Count, params.Rows, params.Spacing = 6, 4, 1
+	case PrefabChain:
+		params.Count = 8
+	case PrefabRing:
+		params.Count, params.Spacing = 12, 1
+	case PrefabCloud:
+		params.Count, params.Spacing = 15, 1
+	}
+	return params
+}
+
+// BuildPrefab creates the entities of a prefab around (x, y). Pyramids rest their
+// base on y and chains hang down from it; the other prefabs are centered there.
+// Chain links are tethered to the link above, and the top link is frozen in place.
+func BuildPrefab(kind PrefabKind, x, y float64, params PrefabParams) []Entity {
+	offsets := prefabLayout(kind, x, y, params)
+	entities := make([]Entity, len(offsets))
+	for i, offset := range offsets {
+		entityType := SphereType
+		if len(params.Types) > 0 {
+			entityType = params.Types[i%len(params.Types)]
+		}
+		t := 0.0
+		if len(offsets) > 1 {
+			t = float64(i) / float64(len(offsets)-1)
+		}
+		color := blendColors(params.From, params.To, t)
+		entity, ok := NewEntityOfType(entityType, x+offset[0], y+offset[1], params.Size, color)
+		if !ok {
+			entity = NewSphere(x+offset[0], y+offset[1], params.Size, color)
+		}
+		entities[i] = entity
+	}
+
+	if kind == PrefabChain && len(entities) > 0 {
+		if pin, ok := entities[0].(freezable); ok {
+			pin.SetFrozen(true)
+		}
+		_, stepY := prefabStep(params)
+		for i := 1; i < len(entities); i++ {
+			if host, ok := entities[i].(interface{ SetBehavior(Behavior) }); ok {
+				host.SetBehavior(&TetherBehavior{Anchor: entities[i-1], Length: stepY})
+			}
+		}
+	}
+	return entities
+}
+
+// prefabStep returns the distance between neighboring entity centers
+func prefabStep(params PrefabParams) (stepX, stepY float64) {
+	width, height := shapeCells(params.Size)
+	spacing := math.Max(0, params.Spacing)
+	return float64(width) + spacing, float64(height) + spacing
+}
+
+// prefabLayout returns each entity's offset from the placement point (x, y), in spawn order
+func prefabLayout(kind PrefabKind, x, y float64, params PrefabParams) [][2]float64 {
+	if params.Count < 1 {
+		return nil
+	}
+	stepX, stepY := prefabStep(params)
+	var offsets [][2]float64
+
+	switch kind {
+	case PrefabPyramid:
+		// Bottom row first; each row above sits in the gaps of the one below
+		for row := 0; row < params.Count; row++ {
+			width := params.Count - row
+			for i := 0; i < width; i++ {
+				offsets = append(offsets, [2]float64{(float64(i) - float64(width-1)/2) * stepX, -float64(row) * stepY})
+			}
+		}
+	case PrefabLattice:
+		rows := max(1, params.Rows)
+		for row := 0; row < rows; row++ {
+			for col := 0; col < params.Count; col++ {
+				offsets = append(offsets, [2]float64{
+					(float64(col) - float64(params.Count-1)/2) * stepX,
+					(float64(row) - float64(rows-1)/2) * stepY,
+				})
+			}
+		}
+	case PrefabChain:
+		for i := 0; i < params.Count; i++ {
+			offsets = append(offsets, [2]float64{0, float64(i) * stepY})
+		}
+	case PrefabRing:
+		// Cells are about twice as tall as they are wide, so the ring is twice as
+		// wide in cells to look round. The vertical radius alone fits every member.
+		step := math.Max(stepX, stepY)
+		radiusY := math.Max(step, float64(params.Count)*step/(2*math.Pi)*1.1)
+		for i := 0; i < params.Count; i++ {
+			angle := 2 * math.Pi * float64(i) / float64(params.Count)
+			offsets = append(offsets, [2]float64{2 * radiusY * math.Cos(angle), radiusY * math.Sin(angle)})
+		}
+	case PrefabCloud:
+		var area [4]float64
+		if params.Area != area {
+			// Keep whole footprints inside, as the walls would push them
+			width, height := shapeCells(params.Size)
+			halfWidth := math.Max(float64(width), float64(params.Size)) / 2
+			halfHeight := math.Max(float64(height), float64(params.Size)) / 2
+			area = [4]float64{
+				params.Area[0] - x + halfWidth, params.Area[1] - y + halfHeight,
+				params.Area[2] - x - halfWidth, params.Area[3] - y - halfHeight,
+			}
+		}
+		offsets = cloudLayout(params.Count, stepX, stepY, area)
+	}
+	return offsets
+}
+
+// cloudLayout scatters count footprints at random in a region that grows until
+// they all fit without overlapping. A nonzero area, as offsets from the placement
+// point, bounds the centers. Random placement can jam a small area before it is
+// full, so a cloud that runs short is laid out again on shuffled grid slots and
+// only falls short when the area has no room left.
+func cloudLayout(count int, stepX, stepY float64, area [4]float64) [][2]float64 {
+	const attemptsPerEntity = 30
+	bounded := area != [4]float64{}
+	step := math.Max(stepX, stepY)
+	radius := math.Sqrt(float64(count)) * step
+	offsets := make([][2]float64, 0, count)
+
+	for len(offsets) < count {
+		minX, minY, maxX, maxY := -radius*2, -radius, radius*2, radius
+		covered := false
+		if bounded {
+			minX, minY = math.Max(minX, area[0]), math.Max(minY, area[1])
+			maxX, maxY = math.Min(maxX, area[2]), math.Min(maxY, area[3])
+			if minX > maxX || minY > maxY {
+				return nil // The area is too small for a single footprint
+			}
+			covered = minX == area[0] && minY == area[1] && maxX == area[2] && maxY == area[3]
+		}
+
+		placed := false
+		for attempt := 0; attempt < attemptsPerEntity && !placed; attempt++ {
+			candidate := [2]float64{minX + rand.Float64()*(maxX-minX), minY + rand.Float64()*(maxY-minY)}
+			placed = true
+			for _, other := range offsets {
+				if math.Abs(candidate[0]-other[0]) < stepX && math.Abs(candidate[1]-other[1]) < stepY {
+					placed = false
+					break
+				}
+			}
+			if placed {
+				offsets = append(offsets, candidate)
+			}
+		}
+		if !placed && covered {
+			if slots := slotLayout(count, stepX, stepY, area); len(slots) > len(offsets) {
+				return slots
+			}
+			return offsets
+		}
+		if !placed {
+			radius += step // Crowded: widen the region
+		}
+	}
+	return offsets
+}
+
+// slotLayout places up to count footprints on randomly chosen slots of the densest
+// grid that fits the area, jittering each within the slack between slots
+func slotLayout(count int, stepX, stepY float64, area [4]float64) [][2]float64 {
+	width, height := area[2]-area[0], area[3]-area[1]
+	cols, rows := int(width/stepX)+1, int(height/stepY)+1
+	spacingX, spacingY := 0.0, 0.0
+	if cols > 1 {
+		spacingX = width / float64(cols-1)
+	}
+	if rows > 1 {
+		spacingY = height / float64(rows-1)
+	}
+	jitterX, jitterY := math.Max(0, spacingX-stepX), math.Max(0, spacingY-stepY)
+
+	slots := rand.Perm(cols * rows)
+	offsets := make([][2]float64, 0, min(count, len(slots)))
+	for _, slot := range slots[:min(count, len(slots))] {
+		col, row := slot%cols, slot/cols
+		x := area[0] + float64(col)*spacingX
+		y := area[1] + float64(row)*spacingY
+		x += (rand.Float64() - 0.5) * jitterX
+		y += (rand.Float64() - 0.5) * jitterY
+		// Slots on the area's edges only move inward
+		offsets = append(offsets, [2]float64{
+			math.Max(area[0], math.Min(x, area[2])),
+			math.Max(area[1], math.Min(y, area[3])),
+		})
+	}
+	return offsets
+}
+
+// blendColors mixes two "#RRGGBB" colors; t of 0 gives from and 1 gives to.
+// Colors in any other form are not blended and from is returned.
+func blendColors(from, to lipgloss.Color, t float64) lipgloss.Color {
+	var r1, g1, b1, r2, g2, b2 int
+	if _, err := fmt.Sscanf(string(from), "#%02x%02x%02x", &r1, &g1, &b1); err != nil {
+		return from
+	}
+	if _, err := fmt.Sscanf(string(to), "#%02x%02x%02x", &r2, &g2, &b2); err != nil {
+		return from
+	}
+	mix := func(a, b int) int { return int(math.Round(float64(a) + (float64(b)-float64(a))*t)) }
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", mix(r1, r2), mix(g1, g2), mix(b1, b2)))
+}
+
+// TetherBehavior keeps an entity within Length of an anchor entity, like a chain link.
+// A slack tether does nothing; a taut one pulls the entity back to Length and
+// cancels its motion away from the anchor.
+type TetherBehavior struct {
+	Anchor Entity
+	Length float64
+}
+
+func (b *TetherBehavior) Update(entity Entity, world World, deltaTime float64) {
+	ax, ay := b.Anchor.GetPosition()
+	x, y := entity.GetPosition()
+	dx, dy := x-ax, y-ay
+	distance := math.Hypot(dx, dy)
+	if distance <= b.Length || distance == 0 {
+		return
+	}
+	nx, ny := dx/distance, dy/distance
+	entity.SetPosition(ax+nx*b.Length, ay+ny*b.Length)
+
+	vx, vy := entity.GetVelocity()
+	avx, avy := b.Anchor.GetVelocity()
+	if outward := (vx-avx)*nx + (vy-avy)*ny; outward > 0 {
+		entity.SetVelocity(vx-outward*nx, vy-outward*ny)
+	}
+}
+
+// placePrefab spawns the selected prefab at (x, y) with the selected type, size and
+// color. Entities outside the walls are dropped, and spawning stops at the entity limit.
+func (m *Model) placePrefab(x, y float64) {
+	params := defaultPrefabParams(m.prefabKind)
+	params.Size = m.selectedEntitySize
+	params.Types = []EntityType{m.selectedEntityType}
+	colors := GetAvailableColors()
+	params.From = colors[m.selectedColorIndex]
+	params.To = colors[(m.selectedColorIndex+1)%len(colors)]
+	m.addPrefab(BuildPrefab(m.prefabKind, x, y, params))
+}
+
+// addPrefab adds the in-bounds entities of a prefab, up to the entity limit, and
+// returns how many were added
+func (m *Model) addPrefab(entities []Entity) int {
+	pe := m.physicsEngine
+	added := 0
+	for _, entity := range entities {
+		if m.entityManager.Count() >= m.maxEntityLimit {
+			break
+		}
+		x, y := entity.GetPosition()
+		if x < pe.MinX || x >= pe.MaxX || y < pe.MinY || y >= pe.MaxY {
+			continue
+		}
+		if host, ok := entity.(behaviorHost); !ok || host.GetBehavior() == nil {
+			m.applySpawnSettings(entity) // Chain links keep their tethers
+		}
+		m.entityManager.AddEntity(entity)
+		added++
+	}
+	return added
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// assertNoOverlap fails if any two entities' footprints overlap
+func assertNoOverlap(t *testing.T, name string, entities []Entity) {
+	t.Helper()
+	for i := range entities {
+		for j := i + 1; j < len(entities); j++ {
+			x1, y1, w1, h1 := entities[i].GetBounds()
+			x2, y2, w2, h2 := entities[j].GetBounds()
+			if x1 < x2+w2-1e-9 && x2 < x1+w1-1e-9 && y1 < y2+h2-1e-9 && y2 < y1+h1-1e-9 {
+				t.Fatalf("%s: entities %d and %d overlap", name, i, j)
+			}
+		}
+	}
+}
+
+func TestPrefabLayouts(t *testing.T) {
+	counts := map[PrefabKind]int{
+		PrefabPyramid: 15, // 5 + 4 + 3 + 2 + 1
+		PrefabLattice: 24, // 6 × 4
+		PrefabChain:   8,
+		PrefabRing:    12,
+		PrefabCloud:   15,
+	}
+	for kind, expected := range counts {
+		for _, size := range []int{1, 4} {
+			params := defaultPrefabParams(kind)
+			params.Size = size
+			params.Spacing = 0
+			entities := BuildPrefab(kind, 50, 50, params)
+			if len(entities) != expected {
+				t.Errorf("%s: expected %d entities, got %d", kind, expected, len(entities))
+			}
+			assertNoOverlap(t, kind.String(), entities)
+		}
+	}
+
+	// Pyramids rest on the placement point, chains hang from it
+	pyramid := BuildPrefab(PrefabPyramid, 50, 50, defaultPrefabParams(PrefabPyramid))
+	if x, y := pyramid[2].GetPosition(); x != 50 || y != 50 {
+		t.Errorf("Expected the middle of the base at the placement point, got (%.1f, %.1f)", x, y)
+	}
+	if _, y := pyramid[len(pyramid)-1].GetPosition(); y != 46 {
+		t.Errorf("Expected the apex four rows up, got %.1f", y)
+	}
+	chain := BuildPrefab(PrefabChain, 50, 10, defaultPrefabParams(PrefabChain))
+	if !isFrozen(chain[0]) || isFrozen(chain[1]) {
+		t.Error("Expected only the top chain link to be pinned")
+	}
+}
+
+func TestPrefabColorGradientAndTypes(t *testing.T) {
+	params := defaultPrefabParams(PrefabLattice)
+	params.From, params.To = lipgloss.Color("#000000"), lipgloss.Color("#FF8000")
+	params.Types = []EntityType{SphereType, SpriteType}
+	entities := BuildPrefab(PrefabLattice, 50, 50, params)
+
+	if entities[0].GetColor() != "#000000" || entities[len(entities)-1].GetColor() != "#FF8000" {
+		t.Errorf("Expected the gradient to run from first to last, got %s to %s",
+			entities[0].GetColor(), entities[len(entities)-1].GetColor())
+	}
+	if middle := blendColors(params.From, params.To, 0.5); middle != "#804000" {
+		t.Errorf("Expected an even blend halfway, got %s", middle)
+	}
+	if entities[0].GetType() != SphereType || entities[1].GetType() != SpriteType {
+		t.Error("Expected the types to alternate entity by entity")
+	}
+}
+
+func TestChainHangsFromPin(t *testing.T) {
+	pe := NewPhysicsEngine(100, 60)
+	params := defaultPrefabParams(PrefabChain)
+	chain := BuildPrefab(PrefabChain, 50, 5, params)
+	chain[len(chain)-1].SetVelocity(20, 0) // Swing the free end sideways
+	world := &simulationWorld{entityManager: NewEntityManager(), physicsEngine: pe}
+
+	for step := 0; step < 300; step++ {
+		ApplyBehaviors(chain, world, pe.DeltaTime)
+		pe.ApplyPhysics(chain)
+		pe.HandleEntityCollisions(chain)
+	}
+
+	if x, y := chain[0].GetPosition(); x != 50 || y != 5 {
+		t.Errorf("Expected the pin to stay put, got (%.1f, %.1f)", x, y)
+	}
+	_, stepY := prefabStep(params)
+	for i := 1; i < len(chain); i++ {
+		x1, y1 := chain[i-1].GetPosition()
+		x2, y2 := chain[i].GetPosition()
+		if gap := math.Hypot(x2-x1, y2-y1); gap > stepY*1.5 {
+			t.Errorf("Expected link %d to stay near the one above, got a gap of %.2f", i, gap)
+		}
+	}
+	if _, y := chain[len(chain)-1].GetPosition(); y > 20 {
+		t.Errorf("Expected the chain to hang rather than fall to the floor, end at y=%.1f", y)
+	}
+}
+
+func TestModelPlacesPrefabAtBrush(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	model.brushX, model.brushY = 40, 8
+
+	press := func(key rune) {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		model = updatedModel.(Model)
+	}
+
+	press('U')
+	if model.prefabKind != PrefabLattice {
+		t.Fatalf("Expected U to cycle to the lattice, got %s", model.prefabKind)
+	}
+	press('u')
+	if model.entityManager.Count() != 24 {
+		t.Fatalf("Expected a 6×4 lattice, got %d entities", model.entityManager.Count())
+	}
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, entity := range model.entityManager.GetEntities() {
+		x, _ := entity.GetPosition()
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+	}
+	if (minX+maxX)/2 != 40.5 {
+		t.Errorf("Expected the lattice centered on the brush, got center %.1f", (minX+maxX)/2)
+	}
+
+	model.entityManager.Clear()
+	model.maxEntityLimit = 10
+	press('u')
+	if model.entityManager.Count() != 10 {
+		t.Errorf("Expected placement to stop at the entity limit, got %d", model.entityManager.Count())
+	}
+
+	model.entityManager.Clear()
+	model.maxEntityLimit = DefaultEntityLimit
+	model.runStressTest()
+	if model.entityManager.Count() == 0 || model.entityManager.Count() > StressTestEntities {
+		t.Fatalf("Expected the stress test to add up to %d entities, got %d", StressTestEntities, model.entityManager.Count())
+	}
+	assertNoOverlap(t, "stress test", model.entityManager.GetEntities())
+
+	// A small world still fits the whole cloud, whatever the random size
+	for i := 0; i < 20; i++ {
+		model.entityManager.Clear()
+		model.SetWorldSize(76, 18)
+		model.runStressTest()
+		if model.entityManager.Count() != StressTestEntities {
+			t.Fatalf("Expected all %d stress test entities on a 76x18 world, got %d", StressTestEntities, model.entityManager.Count())
+		}
+		assertNoOverlap(t, "small world stress test", model.entityManager.GetEntities())
+	}
+
+	// A world with no room for the cloud gets as many as fit, without hanging
+	params := defaultPrefabParams(PrefabCloud)
+	params.Count, params.Size = 50, 4
+	params.Area = [4]float64{0, 0, 20, 8}
+	cloud := BuildPrefab(PrefabCloud, 10, 4, params)
+	if len(cloud) == 0 || len(cloud) >= 50 {
+		t.Errorf("Expected a full area to cut the cloud short, got %d", len(cloud))
+	}
+	assertNoOverlap(t, "full area", cloud)
+	for _, entity := range cloud {
+		if x, y := entity.GetPosition(); x < 0 || x > 20 || y < 0 || y > 8 {
+			t.Fatalf("Expected the cloud inside its area, found (%.1f, %.1f)", x, y)
+		}
+	}
+}
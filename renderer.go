@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Frame describes one frame of the simulation pane: the composited cells of every
+// layer and the text around them, with no styling applied. A Renderer turns it
+// into output.
+type Frame struct {
+	Width       int         // Columns available to the pane's content
+	Title       string      // Already shortened to fit Width
+	Performance bool        // Performance mode frames are styled to stand out
+	Grid        *Compositor // Simulation cells by layer: entities, overlays and the rest
+	Info        []string    // Physics and settings lines; in performance mode the second is debug info
+	Status      StatusBar
+}
+
+// StatusBar is the last line of the pane
+type StatusBar struct {
+	EntityCount int
+	Entities    string // "Entities: 3", with the limit in performance mode
+	Types       string // Count of each registered entity type
+	FPS         float64
+	State       string // Running or paused, with the score once zones are placed
+}
+
+// Renderer produces the simulation pane's output from a frame
+type Renderer interface {
+	Render(frame Frame) string
+}
+
+// LipglossRenderer renders frames for a color terminal
+type LipglossRenderer struct{}
+
+func (LipglossRenderer) Render(frame Frame) string {
+	var lines []string
+	lines = append(lines, titleStyle.Width(frame.Width).Render(frame.Title))
+
+	// Add decorative separator (ensure it fits)
+	separator := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#4A90E2")).
+		Render(strings.Repeat("─", max(1, frame.Width-4)))
+	lines = append(lines, "  "+separator)
+
+	for _, row := range frame.Grid.Flatten() {
+		var line strings.Builder
+		for _, cell := range row {
+			line.WriteString(styleCell(cell))
+		}
+		lines = append(lines, line.String())
+	}
+
+	for i, info := range frame.Info {
+		switch {
+		case frame.Performance && i == 0:
+			lines = append(lines, performanceModeStyle.Render(info))
+		case frame.Performance:
+			lines = append(lines, statusStyle.Render(info))
+		default:
+			lines = append(lines, physicsInfoStyle.Render(info))
+		}
+	}
+
+	lines = append(lines, renderStatusBar(frame.Status, frame.Width))
+	return strings.Join(lines, "\n")
+}
+
+// styleCell renders a cell's glyph with its colors and attributes
+func styleCell(cell Cell) string {
+	if cell == (Cell{Glyph: cell.Glyph}) {
+		return cell.Glyph
+	}
+	style := lipgloss.NewStyle().Bold(cell.Bold).Faint(cell.Faint).Reverse(cell.Reverse)
+	if cell.Color != "" {
+		style = style.Foreground(cell.Color)
+	}
+	if cell.Background != "" {
+		style = style.Background(cell.Background)
+	}
+	return style.Render(cell.Glyph)
+}
+
+// renderStatusBar styles the status line, dropping parts that do not fit the width
+func renderStatusBar(status StatusBar, width int) string {
+	entityDisplay := entityCountStyle.Render(status.Entities)
+	typeDisplay := statusStyle.Render(status.Types)
+	fpsDisplay := statusStyle.Render(fmt.Sprintf("FPS: %.1f", status.FPS))
+	stateDisplay := statusStyle.Render(status.State)
+	divider := lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(" │ ")
+
+	// Create responsive status line based on available width
+	var statusLine string
+	if width < 20 {
+		// Ultra minimal: just entity count and FPS (essential info)
+		statusLine = fmt.Sprintf("%s FPS: %.1f", status.Entities, status.FPS)
+	} else if width < 40 {
+		// Compact: entity count, FPS, and status
+		statusLine = lipgloss.JoinHorizontal(lipgloss.Left, entityDisplay, divider, fpsDisplay, divider, stateDisplay)
+	} else {
+		// Full status line for larger screens
+		statusLine = lipgloss.JoinHorizontal(lipgloss.Left,
+			entityDisplay, divider, typeDisplay, divider, fpsDisplay, divider, stateDisplay)
+	}
+
+	// Smart truncation - preserve essential information (Entities and FPS)
+	if lipgloss.Width(statusLine) > width {
+		// If full status line is too long, fall back to essential info
+		essentialStatus := fmt.Sprintf("Entities: %d FPS: %.1f", status.EntityCount, status.FPS)
+		if len([]rune(essentialStatus)) <= width {
+			statusLine = essentialStatus
+		} else {
+			// Last resort: truncate but ensure it's valid
+			statusLine = string([]rune(statusLine)[:max(1, width-3)]) + "..."
+		}
+	}
+	return statusLine
+}
+
+// plainStatusLine joins the status bar's parts without styling
+func plainStatusLine(status StatusBar) string {
+	return strings.Join([]string{status.Entities, status.Types, fmt.Sprintf("FPS: %.1f", status.FPS), status.State}, " | ")
+}
+
+// PlainRenderer renders frames as plain ASCII with no escape codes, for logs,
+// pipes and terminals without Unicode
+type PlainRenderer struct{}
+
+func (PlainRenderer) Render(frame Frame) string {
+	lines := []string{asciiText(frame.Title), strings.Repeat("-", max(1, frame.Width))}
+	for _, row := range frame.Grid.Flatten() {
+		var line strings.Builder
+		for _, cell := range row {
+			line.WriteString(asciiGlyph(cell.Glyph))
+		}
+		lines = append(lines, line.String())
+	}
+	for _, info := range frame.Info {
+		lines = append(lines, asciiText(info))
+	}
+	lines = append(lines, asciiText(plainStatusLine(frame.Status)))
+	return strings.Join(lines, "\n")
+}
+
+// asciiGlyphs stand in for the Unicode glyphs drawn in the grid
+var asciiGlyphs = map[string]string{
+	"●": "o", "◆": "*", "■": "#", "▲": "^", "▼": "v", "◀": "<", "▶": ">",
+	"➤": ">", "·": ".", "∙": ".", "•": "o", "✦": "*",
+	"░": ".", "▒": ":", "▓": "%", "█": "#", "▀": "\"", "▄": "_", "▮": "|", "▬": "=",
+	"↑": "^", "↓": "v", "←": "<", "→": ">", "↖": "\\", "↗": "/", "↘": "\\", "↙": "/",
+}
+
+// asciiGlyph returns an ASCII stand-in for a one-cell glyph: braille becomes ':'
+// and anything else unknown '#'
+func asciiGlyph(glyph string) string {
+	if glyph == "" {
+		return " "
+	}
+	if ascii, ok := asciiGlyphs[glyph]; ok {
+		return ascii
+	}
+	r := []rune(glyph)[0]
+	switch {
+	case r < 0x80:
+		return string(r)
+	case r >= 0x2800 && r <= 0x28FF:
+		return ":"
+	}
+	return "#"
+}
+
+// asciiText replaces the known glyphs in a line of text and drops other non-ASCII
+// characters, such as emoji icons, along with the spaces they leave behind
+func asciiText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if ascii, ok := asciiGlyphs[string(r)]; ok {
+			b.WriteString(ascii)
+		} else if r < 0x80 {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// CellBuffer is a headless renderer that keeps the last frame in memory, so tests
+// can check what was drawn cell by cell without parsing escape codes
+type CellBuffer struct {
+	Frame Frame
+	cells [][]Cell // Composited
+}
+
+// NewCellBuffer creates an empty cell buffer
+func NewCellBuffer() *CellBuffer {
+	return &CellBuffer{}
+}
+
+// Render keeps the frame and returns it as plain text, with Unicode glyphs intact
+func (b *CellBuffer) Render(frame Frame) string {
+	b.Frame = frame
+	b.cells = frame.Grid.Flatten()
+	lines := []string{frame.Title}
+	for _, row := range b.cells {
+		var line strings.Builder
+		for _, cell := range row {
+			line.WriteString(cell.Glyph)
+		}
+		lines = append(lines, line.String())
+	}
+	lines = append(lines, frame.Info...)
+	lines = append(lines, plainStatusLine(frame.Status))
+	return strings.Join(lines, "\n")
+}
+
+// Size returns the grid size of the last frame
+func (b *CellBuffer) Size() (width, height int) {
+	if len(b.cells) == 0 {
+		return 0, 0
+	}
+	return len(b.cells[0]), len(b.cells)
+}
+
+// Cell returns the cell shown at (x, y), or an empty cell outside the grid
+func (b *CellBuffer) Cell(x, y int) Cell {
+	if y < 0 || y >= len(b.cells) || x < 0 || x >= len(b.cells[y]) {
+		return Cell{}
+	}
+	return b.cells[y][x]
+}
+
+// Glyph returns the glyph shown at a cell, or "" outside the grid
+func (b *CellBuffer) Glyph(x, y int) string {
+	return b.Cell(x, y).Glyph
+}
+
+// LayerGlyph returns the glyph one layer drew at a cell; "" if it drew nothing there
+func (b *CellBuffer) LayerGlyph(layer Layer, x, y int) string {
+	grid := b.Frame.Grid
+	if grid == nil || y < 0 || y >= grid.Height || x < 0 || x >= grid.Width {
+		return ""
+	}
+	return grid.Layer(layer)[y][x].Glyph
+}
+
+// Count returns how many cells show a glyph
+func (b *CellBuffer) Count(glyph string) int {
+	count := 0
+	for _, row := range b.cells {
+		for _, cell := range row {
+			if cell.Glyph == glyph {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Find returns the first cell, scanning rows top to bottom, that shows a glyph
+func (b *CellBuffer) Find(glyph string) (x, y int, ok bool) {
+	for y, row := range b.cells {
+		for x, cell := range row {
+			if cell.Glyph == glyph {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newBufferedModel returns a sized model that renders into a cell buffer
+func newBufferedModel(t *testing.T) (Model, *CellBuffer) {
+	t.Helper()
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	return model, buffer
+}
+
+func TestCellBufferRecordsFrame(t *testing.T) {
+	model, buffer := newBufferedModel(t)
+	sphere := NewSphere(12.5, 6.5, 1, lipgloss.Color("#FF0000"))
+	sphere.SetFrozen(true)
+	model.entityManager.AddEntity(sphere)
+	model.sensors.Add(NewSensor(30, 2, 6, 4))
+
+	text := model.renderSimulation()
+	if strings.Contains(text, "\x1b[") {
+		t.Error("Expected the cell buffer's text to have no escape codes")
+	}
+	if buffer.Glyph(12, 6) != "●" || buffer.LayerGlyph(LayerEntities, 12, 6) != "●" {
+		t.Errorf("Expected the sphere at cell (12, 6), got %q", buffer.Glyph(12, 6))
+	}
+	if x, y, ok := buffer.Find("●"); !ok || x != 12 || y != 6 || buffer.Count("●") != 1 {
+		t.Errorf("Expected exactly one sphere, found at (%d, %d)", x, y)
+	}
+	if buffer.LayerGlyph(LayerFields, 30, 2) == "" || buffer.LayerGlyph(LayerEntities, 30, 2) != "" {
+		t.Error("Expected the zone outline on the field layer only")
+	}
+	if width, height := buffer.Size(); width != buffer.Frame.Grid.Width || height != buffer.Frame.Grid.Height {
+		t.Errorf("Expected the buffer to match the frame grid, got %dx%d", width, height)
+	}
+	if buffer.Frame.Status.EntityCount != 1 || !strings.Contains(text, "Entities: 1") {
+		t.Error("Expected the status bar in the frame and the text")
+	}
+}
+
+func TestPlainRendererIsASCII(t *testing.T) {
+	model, _ := newBufferedModel(t)
+	model.renderer = PlainRenderer{}
+	sphere := NewSphere(12.5, 6.5, 1, lipgloss.Color("#FF0000"))
+	sphere.SetFrozen(true)
+	model.entityManager.AddEntity(sphere)
+
+	text := model.renderSimulation()
+	for _, r := range text {
+		if r >= 0x80 {
+			t.Fatalf("Expected plain ASCII output, found %q", r)
+		}
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) < 9 || lines[8][12] != 'o' {
+		t.Error("Expected the sphere drawn as o in the grid")
+	}
+	if !strings.Contains(text, "Gravity: 25.0") || !strings.Contains(text, "Entities: 1") {
+		t.Error("Expected the info and status lines without their icons")
+	}
+
+	if glyph := asciiGlyph("⣿"); glyph != ":" {
+		t.Errorf("Expected braille to become ':', got %q", glyph)
+	}
+}
+
+func TestLipglossRendererKeepsLayout(t *testing.T) {
+	model, buffer := newBufferedModel(t)
+	model.renderSimulation()
+	frame := buffer.Frame
+
+	text := LipglossRenderer{}.Render(frame)
+	lines := strings.Split(text, "\n")
+	if !strings.Contains(lines[0], frame.Title) {
+		t.Error("Expected the title on the first line")
+	}
+	if !strings.Contains(text, "⚙️ Gravity") {
+		t.Error("Expected the styled info line")
+	}
+	if cell := (Cell{Glyph: "●", Color: lipgloss.Color("#FF0000"), Bold: true}); !strings.Contains(styleCell(cell), "●") {
+		t.Error("Expected a styled cell to keep its glyph")
+	}
+	if styleCell(Cell{Glyph: " "}) != " " {
+		t.Error("Expected a cell with no style to be written as is")
+	}
+	frame.Width = 15
+	if status := renderStatusBar(frame.Status, frame.Width); lipgloss.Width(status) > 15 {
+		t.Errorf("Expected the status bar to fit a narrow pane, got %q", status)
+	}
+}
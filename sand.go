@@ -0,0 +1,449 @@
+package main
+
+import (
+	"math/rand"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Material is the contents of one cell in the falling-sand layer
+type Material uint8
+
+const (
+	MaterialEmpty Material = iota
+	MaterialSand
+	MaterialWater
+	MaterialStone
+	MaterialFire
+	MaterialSmoke
+)
+
+// Lifetimes (in ticks) for transient materials
+const (
+	fireMinLife  = 12
+	fireMaxLife  = 30
+	smokeMinLife = 20
+	smokeMaxLife = 45
+)
+
+// materialBrush is an entry in the paint palette cycled with the m key
+type materialBrush struct {
+	Name     string
+	Material Material
+}
+
+// materialBrushes lists paint modes in cycle order. The first entry turns painting off.
+var materialBrushes = []materialBrush{
+	{Name: "Off", Material: MaterialEmpty},
+	{Name: "Sand", Material: MaterialSand},
+	{Name: "Water", Material: MaterialWater},
+	{Name: "Stone", Material: MaterialStone},
+	{Name: "Fire", Material: MaterialFire},
+	{Name: "Smoke", Material: MaterialSmoke},
+	{Name: "Erase", Material: MaterialEmpty},
+}
+
+// Material appearance
+var (
+	materialGlyphs = map[Material]string{
+		MaterialSand:  "▓",
+		MaterialWater: "≈",
+		MaterialStone: "█",
+		MaterialFire:  "^",
+		MaterialSmoke: "░",
+	}
+	materialColors = map[Material]lipgloss.Color{
+		MaterialSand:  lipgloss.Color("#E2C044"),
+		MaterialWater: lipgloss.Color("#1E90FF"),
+		MaterialStone: lipgloss.Color("#8A8A8A"),
+		MaterialFire:  lipgloss.Color("#FF4500"),
+		MaterialSmoke: lipgloss.Color("#555555"),
+	}
+)
+
+// MaterialGrid is a cellular-automaton layer of materials that lives beneath the entities.
+// Cell coordinates match the render grid: column x, row y.
+type MaterialGrid struct {
+	Width, Height int
+
+	// DownX, DownY is the unit cell step loose material falls along; (0, 1) is straight down
+	DownX, DownY int
+
+	cells []Material
+	life  []int    // Remaining ticks for fire and smoke
+	stamp []uint32 // Frame on which a cell was last written, so nothing moves twice per step
+	frame uint32
+}
+
+// NewMaterialGrid creates an empty material layer
+func NewMaterialGrid(width, height int) *MaterialGrid {
+	g := &MaterialGrid{DownY: 1}
+	g.Resize(width, height)
+	return g
+}
+
+// Resize changes the grid dimensions, keeping cells that still fit.
+// Cells are anchored to the side gravity points at so piles stay on the floor when the pane changes.
+func (g *MaterialGrid) Resize(width, height int) {
+	width = max(0, width)
+	height = max(0, height)
+	if width == g.Width && height == g.Height {
+		return
+	}
+
+	offsetX, offsetY := 0, 0
+	if g.DownX > 0 {
+		offsetX = width - g.Width
+	}
+	if g.DownY > 0 {
+		offsetY = height - g.Height
+	}
+
+	cells := make([]Material, width*height)
+	life := make([]int, width*height)
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			newX, newY := x+offsetX, y+offsetY
+			if newX < 0 || newY < 0 || newX >= width || newY >= height {
+				continue
+			}
+			cells[newY*width+newX] = g.cells[y*g.Width+x]
+			life[newY*width+newX] = g.life[y*g.Width+x]
+		}
+	}
+
+	g.Width, g.Height = width, height
+	g.cells = cells
+	g.life = life
+	g.stamp = make([]uint32, width*height)
+}
+
+// SetDown sets the direction loose material falls; it must be a unit step along one axis
+func (g *MaterialGrid) SetDown(dx, dy int) {
+	if dx*dx+dy*dy != 1 {
+		return
+	}
+	g.DownX, g.DownY = dx, dy
+}
+
+// InBounds reports whether (x, y) is a cell of the grid
+func (g *MaterialGrid) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+// Get returns the material at (x, y); cells outside the grid are empty
+func (g *MaterialGrid) Get(x, y int) Material {
+	if !g.InBounds(x, y) {
+		return MaterialEmpty
+	}
+	return g.cells[y*g.Width+x]
+}
+
+// Set places a material at (x, y)
+func (g *MaterialGrid) Set(x, y int, material Material) {
+	if !g.InBounds(x, y) {
+		return
+	}
+	i := y*g.Width + x
+	g.cells[i] = material
+	switch material {
+	case MaterialFire:
+		g.life[i] = fireMinLife + rand.Intn(fireMaxLife-fireMinLife)
+	case MaterialSmoke:
+		g.life[i] = smokeMinLife + rand.Intn(smokeMaxLife-smokeMinLife)
+	default:
+		g.life[i] = 0
+	}
+}
+
+// Paint fills a disc of the given radius around (cx, cy).
+// Loose materials are scattered so poured sand and water look natural.
+func (g *MaterialGrid) Paint(cx, cy, radius int, material Material) {
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			if material != MaterialEmpty && material != MaterialStone && rand.Float64() < 0.4 {
+				continue
+			}
+			g.Set(x, y, material)
+		}
+	}
+}
+
+// Clear empties every cell
+func (g *MaterialGrid) Clear() {
+	for i := range g.cells {
+		g.cells[i] = MaterialEmpty
+		g.life[i] = 0
+	}
+}
+
+// Count returns the number of cells holding a material
+func (g *MaterialGrid) Count(material Material) int {
+	count := 0
+	for _, cell := range g.cells {
+		if cell == material {
+			count++
+		}
+	}
+	return count
+}
+
+// IsSolid reports whether entities should bounce off the cell at (x, y)
+func (g *MaterialGrid) IsSolid(x, y int) bool {
+	switch g.Get(x, y) {
+	case MaterialSand, MaterialStone:
+		return true
+	}
+	return false
+}
+
+// Render returns the glyph and color for the cell at (x, y), or an empty cell
+func (g *MaterialGrid) Render(x, y int) Cell {
+	material := g.Get(x, y)
+	if material == MaterialEmpty {
+		return Cell{}
+	}
+	color := materialColors[material]
+	if material == MaterialFire && g.life[y*g.Width+x]%3 == 0 {
+		color = lipgloss.Color("#FFA500") // Flicker
+	}
+	return Cell{Glyph: materialGlyphs[material], Color: color}
+}
+
+// Step advances the automaton by one tick
+func (g *MaterialGrid) Step() {
+	g.frame++
+
+	// Scan from the floor up so falling material moves into cells already processed,
+	// alternating sideways direction each frame to avoid a bias
+	depth, span := g.Height, g.Width
+	if g.DownX != 0 {
+		depth, span = g.Width, g.Height
+	}
+	forward := g.frame%2 == 0
+	for layer := 0; layer < depth; layer++ {
+		for i := 0; i < span; i++ {
+			j := i
+			if !forward {
+				j = span - 1 - i
+			}
+			x, y := g.scanCell(layer, j)
+			idx := y*g.Width + x
+			if g.stamp[idx] == g.frame {
+				continue
+			}
+
+			switch g.cells[idx] {
+			case MaterialSand:
+				g.stepSand(x, y)
+			case MaterialWater:
+				g.stepWater(x, y)
+			case MaterialFire:
+				g.stepFire(x, y)
+			case MaterialSmoke:
+				g.stepSmoke(x, y)
+			}
+		}
+	}
+}
+
+// scanCell maps a scan position to a cell. Layer 0 is the floor gravity points at
+// and i runs along it.
+func (g *MaterialGrid) scanCell(layer, i int) (x, y int) {
+	switch {
+	case g.DownX > 0:
+		return g.Width - 1 - layer, i
+	case g.DownX < 0:
+		return layer, i
+	case g.DownY < 0:
+		return i, layer
+	}
+	return i, g.Height - 1 - layer
+}
+
+// neighbor returns the cell reached from (x, y) by moving side steps across gravity
+// and down steps along it. Negative down moves against gravity.
+func (g *MaterialGrid) neighbor(x, y, side, down int) (int, int) {
+	sideX, sideY := g.DownY, -g.DownX
+	return x + side*sideX + down*g.DownX, y + side*sideY + down*g.DownY
+}
+
+// stepSand falls straight down or slides diagonally, sinking through liquids and gas
+func (g *MaterialGrid) stepSand(x, y int) {
+	sinksInto := func(m Material) bool {
+		return m == MaterialEmpty || m == MaterialWater || m == MaterialSmoke
+	}
+	dir := g.randomDirection()
+	for _, side := range []int{0, dir, -dir} {
+		nx, ny := g.neighbor(x, y, side, 1)
+		if g.InBounds(nx, ny) && sinksInto(g.Get(nx, ny)) {
+			g.swap(x, y, nx, ny)
+			return
+		}
+	}
+}
+
+// stepWater falls, then flows sideways to level out
+func (g *MaterialGrid) stepWater(x, y int) {
+	if g.douse(x, y, MaterialFire) {
+		return
+	}
+	entersInto := func(m Material) bool {
+		return m == MaterialEmpty || m == MaterialSmoke
+	}
+	dir := g.randomDirection()
+	for _, move := range [][2]int{{0, 1}, {dir, 1}, {-dir, 1}, {dir, 0}, {-dir, 0}} {
+		nx, ny := g.neighbor(x, y, move[0], move[1])
+		if g.InBounds(nx, ny) && entersInto(g.Get(nx, ny)) {
+			g.swap(x, y, nx, ny)
+			return
+		}
+	}
+}
+
+// douse turns a cell and an adjacent cell holding the other material into steam.
+// Fire and water put each other out whichever of them is processed first.
+func (g *MaterialGrid) douse(x, y int, other Material) bool {
+	for _, n := range [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+		if g.Get(x+n[0], y+n[1]) == other {
+			g.Set(x, y, MaterialSmoke)
+			g.Set(x+n[0], y+n[1], MaterialSmoke)
+			g.stamp[y*g.Width+x] = g.frame
+			g.stamp[(y+n[1])*g.Width+x+n[0]] = g.frame
+			return true
+		}
+	}
+	return false
+}
+
+// stepFire burns out into smoke, is doused by water and flickers upward
+func (g *MaterialGrid) stepFire(x, y int) {
+	idx := y*g.Width + x
+	if g.douse(x, y, MaterialWater) {
+		return
+	}
+
+	g.life[idx]--
+	if g.life[idx] <= 0 {
+		g.Set(x, y, MaterialSmoke)
+		g.stamp[idx] = g.frame
+		return
+	}
+
+	if rand.Float64() < 0.3 {
+		nx, ny := g.neighbor(x, y, g.randomDirection()*rand.Intn(2), -1)
+		if g.InBounds(nx, ny) && g.Get(nx, ny) == MaterialEmpty {
+			g.swap(x, y, nx, ny)
+		}
+	}
+}
+
+// stepSmoke rises and drifts until it dissipates
+func (g *MaterialGrid) stepSmoke(x, y int) {
+	idx := y*g.Width + x
+	g.life[idx]--
+	if g.life[idx] <= 0 {
+		g.Set(x, y, MaterialEmpty)
+		return
+	}
+
+	dir := g.randomDirection()
+	for _, move := range [][2]int{{0, -1}, {dir, -1}, {-dir, -1}, {dir, 0}} {
+		nx, ny := g.neighbor(x, y, move[0], move[1])
+		if g.InBounds(nx, ny) && g.Get(nx, ny) == MaterialEmpty {
+			g.swap(x, y, nx, ny)
+			return
+		}
+	}
+}
+
+// swap exchanges two cells and marks the destination as updated this frame
+func (g *MaterialGrid) swap(x1, y1, x2, y2 int) {
+	a := y1*g.Width + x1
+	b := y2*g.Width + x2
+	g.cells[a], g.cells[b] = g.cells[b], g.cells[a]
+	g.life[a], g.life[b] = g.life[b], g.life[a]
+	g.stamp[a] = g.frame
+	g.stamp[b] = g.frame
+}
+
+// randomDirection returns -1 or 1
+func (g *MaterialGrid) randomDirection() int {
+	if rand.Intn(2) == 0 {
+		return -1
+	}
+	return 1
+}
+
+// handleMaterialCollisions bounces an entity off solid cells of the material layer.
+// Multi-cell entities collide with every cell of their drawn shape. The entity's
+// previous position is reconstructed from its velocity so each axis can be resolved
+// separately, the same way walls are handled.
+func (pe *PhysicsEngine) handleMaterialCollisions(entity Entity) {
+	grid := pe.Materials
+	if grid == nil || !pe.collidesWithObstacles(entity) {
+		return
+	}
+
+	x, y := entity.GetPosition()
+	vx, vy := entity.GetVelocity()
+	prevX := x - vx*pe.DeltaTime
+	prevY := y - vy*pe.DeltaTime
+	cell := func(v float64) int {
+		if v < 0 {
+			return -1
+		}
+		return int(v)
+	}
+
+	mask := shapeMask(entity)
+	solid := func(cx, cy float64) bool {
+		if mask == nil {
+			return grid.IsSolid(cell(cx), cell(cy))
+		}
+		left, top := drawnOrigin(cx, cy, len(mask[0]), len(mask))
+		for dy, row := range mask {
+			for dx, filled := range row {
+				if filled && grid.IsSolid(left+dx, top+dy) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if !solid(x, y) {
+		return
+	}
+
+	newX, newY := x, y
+	newVX, newVY := vx, vy
+	if solid(x, prevY) {
+		// Moving sideways into a solid cell
+		newX = prevX
+		newVX = -vx * pe.Restitution
+	}
+	if solid(newX, y) {
+		// Landing on (or hitting the underside of) a solid cell
+		newY = prevY
+		newVY = -vy * pe.Restitution
+	}
+
+	// Material may have piled up on top of the entity; lift it out against gravity
+	for lift := 0; solid(newX, newY) && lift < max(grid.Width, grid.Height); lift++ {
+		if grid.DownX != 0 {
+			newX = float64(cell(newX)) + 0.5 - float64(grid.DownX)
+			newVX = 0
+		} else {
+			newY = float64(cell(newY)) + 0.5 - float64(grid.DownY)
+			newVY = 0
+		}
+	}
+
+	entity.SetImmediatePosition(newX, newY)
+	entity.SetVelocity(newVX, newVY)
+}
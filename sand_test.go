@@ -0,0 +1,272 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestMaterialGridSandFalls(t *testing.T) {
+	g := NewMaterialGrid(5, 5)
+	g.Set(2, 0, MaterialSand)
+
+	for i := 0; i < 10; i++ {
+		g.Step()
+	}
+
+	if g.Get(2, 4) != MaterialSand {
+		t.Error("Expected sand to fall to the bottom row")
+	}
+	if g.Count(MaterialSand) != 1 {
+		t.Errorf("Expected sand to be conserved, got %d cells", g.Count(MaterialSand))
+	}
+}
+
+func TestMaterialGridSandPiles(t *testing.T) {
+	g := NewMaterialGrid(5, 3)
+	g.Set(2, 2, MaterialStone)
+	g.Set(2, 1, MaterialSand)
+
+	for i := 0; i < 5; i++ {
+		g.Step()
+	}
+
+	// Sand rests on stone or slides off diagonally, but never passes through it
+	if g.Get(2, 2) != MaterialStone {
+		t.Error("Stone should not move")
+	}
+	if g.Get(1, 2) != MaterialSand && g.Get(3, 2) != MaterialSand {
+		t.Error("Expected sand to slide off the stone onto the floor")
+	}
+}
+
+func TestMaterialGridWaterLevels(t *testing.T) {
+	g := NewMaterialGrid(6, 2)
+	g.Set(0, 0, MaterialWater)
+	g.Set(0, 1, MaterialWater)
+
+	for i := 0; i < 30; i++ {
+		g.Step()
+	}
+
+	if g.Get(0, 0) != MaterialEmpty {
+		t.Error("Expected stacked water to spread out along the floor")
+	}
+	if g.Count(MaterialWater) != 2 {
+		t.Errorf("Expected water to be conserved, got %d cells", g.Count(MaterialWater))
+	}
+}
+
+func TestMaterialGridSandSinksInWater(t *testing.T) {
+	g := NewMaterialGrid(1, 2)
+	g.Set(0, 0, MaterialSand)
+	g.Set(0, 1, MaterialWater)
+
+	g.Step()
+
+	if g.Get(0, 1) != MaterialSand || g.Get(0, 0) != MaterialWater {
+		t.Error("Expected sand to swap places with the water below it")
+	}
+}
+
+func TestMaterialGridFireBurnsOut(t *testing.T) {
+	g := NewMaterialGrid(3, 10)
+	g.Set(1, 9, MaterialFire)
+
+	for i := 0; i < fireMaxLife+smokeMaxLife+5; i++ {
+		g.Step()
+	}
+
+	if g.Count(MaterialFire) != 0 || g.Count(MaterialSmoke) != 0 {
+		t.Errorf("Expected fire to burn out and smoke to dissipate, got %d fire, %d smoke",
+			g.Count(MaterialFire), g.Count(MaterialSmoke))
+	}
+}
+
+func TestMaterialGridWaterDousesFire(t *testing.T) {
+	g := NewMaterialGrid(3, 1)
+	g.Set(0, 0, MaterialFire)
+	g.Set(1, 0, MaterialWater)
+
+	g.Step()
+
+	if g.Count(MaterialFire) != 0 {
+		t.Error("Expected water to extinguish adjacent fire")
+	}
+}
+
+func TestMaterialGridResizeKeepsFloor(t *testing.T) {
+	g := NewMaterialGrid(4, 4)
+	g.Set(1, 3, MaterialStone)
+
+	g.Resize(6, 8)
+
+	if g.Get(1, 7) != MaterialStone {
+		t.Error("Expected floor material to stay on the bottom row after resize")
+	}
+
+	g.Resize(1, 1)
+	if g.Count(MaterialStone) != 0 {
+		t.Error("Expected cells outside the new size to be dropped")
+	}
+}
+
+func TestEntityLandsOnSolidMaterial(t *testing.T) {
+	pe := NewPhysicsEngine(20, 20)
+	pe.Gravity = 0
+	pe.Materials = NewMaterialGrid(20, 20)
+	for x := 0; x < 20; x++ {
+		pe.Materials.Set(x, 10, MaterialStone)
+	}
+
+	sphere := NewSphere(5.5, 9.8, 1, lipgloss.Color("32"))
+	sphere.SetVelocity(0, 5)
+
+	pe.ApplyPhysics([]Entity{sphere})
+
+	x, y := sphere.GetPosition()
+	if int(y) >= 10 {
+		t.Errorf("Expected sphere to stay above the stone row, got (%.2f, %.2f)", x, y)
+	}
+	if _, vy := sphere.GetVelocity(); vy >= 0 {
+		t.Errorf("Expected sphere to bounce up off the stone, got vy=%.2f", vy)
+	}
+}
+
+func TestLargeEntityLandsOnItsFootprint(t *testing.T) {
+	pe := NewPhysicsEngine(40, 40)
+	pe.Materials = NewMaterialGrid(40, 40)
+	for x := 0; x < 40; x++ {
+		pe.Materials.Set(x, 20, MaterialStone)
+	}
+
+	// A size-5 sphere is 7×4 cells, so its bottom row is two cells below its center
+	sphere := NewSphere(20.5, 10, 5, lipgloss.Color("32"))
+	for step := 0; step < 100; step++ {
+		pe.ApplyPhysics([]Entity{sphere})
+	}
+
+	mask := shapeMask(sphere)
+	x, y := sphere.GetPosition()
+	left, top := drawnOrigin(x, y, len(mask[0]), len(mask))
+	for dy, row := range mask {
+		for dx, filled := range row {
+			if filled && pe.Materials.IsSolid(left+dx, top+dy) {
+				t.Fatalf("Expected the sphere to rest on the stone, but cell (%d, %d) sank into it", left+dx, top+dy)
+			}
+		}
+	}
+	if top+len(mask) < 19 {
+		t.Errorf("Expected the sphere to fall onto the stone, its bottom row is at %d", top+len(mask)-1)
+	}
+}
+
+func TestEntityIgnoresLiquidMaterial(t *testing.T) {
+	pe := NewPhysicsEngine(20, 20)
+	pe.Gravity = 0
+	pe.Materials = NewMaterialGrid(20, 20)
+	pe.Materials.Set(5, 10, MaterialWater)
+
+	sphere := NewSphere(5.5, 9.8, 1, lipgloss.Color("32"))
+	sphere.SetVelocity(0, 5)
+
+	pe.ApplyPhysics([]Entity{sphere})
+
+	if _, y := sphere.GetPosition(); int(y) != 10 {
+		t.Errorf("Expected sphere to move into the water cell, got y=%.2f", y)
+	}
+}
+
+func TestModelMaterialPainting(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model = updatedModel.(Model)
+
+	gridWidth, gridHeight := model.simGridSize()
+	if model.materials.Width != gridWidth || model.materials.Height != gridHeight {
+		t.Fatalf("Expected material grid %dx%d to match render grid %dx%d",
+			model.materials.Width, model.materials.Height, gridWidth, gridHeight)
+	}
+
+	// Select stone and paint it with the mouse
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model = updatedModel.(Model)
+	if materialBrushes[model.materialBrush].Material != MaterialStone {
+		t.Fatalf("Expected stone brush, got %s", materialBrushes[model.materialBrush].Name)
+	}
+
+	updatedModel, _ = model.Update(tea.MouseMsg{
+		X: SimGridOriginX + 10, Y: SimGridOriginY + 5,
+		Button: tea.MouseButtonLeft, Action: tea.MouseActionPress,
+	})
+	model = updatedModel.(Model)
+	if model.materials.Get(10, 5) != MaterialStone {
+		t.Error("Expected mouse press to paint stone at the cursor")
+	}
+
+	// Keyboard painting at the brush cursor
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	model = updatedModel.(Model)
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	model = updatedModel.(Model)
+	if model.materials.Get(12, 5) != MaterialStone {
+		t.Error("Expected n to paint stone at the moved brush cursor")
+	}
+
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	model.renderSimulation()
+	if cell := buffer.Cell(12, 5); cell != (Cell{Glyph: materialGlyphs[MaterialStone], Color: materialColors[MaterialStone]}) {
+		t.Errorf("Expected stone to be drawn in the simulation grid, got %+v", cell)
+	}
+
+	// Reset clears the layer
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	model = updatedModel.(Model)
+	if model.materials.Count(MaterialStone) != 0 {
+		t.Error("Expected reset to clear the material layer")
+	}
+}
+
+func TestMaterialGridSandFallsSideways(t *testing.T) {
+	pe := NewPhysicsEngine(5, 5)
+	pe.Materials = NewMaterialGrid(5, 5)
+	pe.SetGravityAngle(0) // Gravity pulls right
+	pe.Materials.Set(0, 2, MaterialSand)
+
+	for i := 0; i < 10; i++ {
+		pe.Materials.Step()
+	}
+
+	if pe.Materials.Get(4, 2) != MaterialSand {
+		t.Error("Expected sand to fall to the right edge")
+	}
+
+	// Piles stay against the right edge when the grid widens
+	pe.Materials.Resize(8, 5)
+	if pe.Materials.Get(7, 2) != MaterialSand {
+		t.Error("Expected resize to keep material anchored to the side gravity points at")
+	}
+}
+
+func TestEntityLiftedAgainstSidewaysGravity(t *testing.T) {
+	pe := NewPhysicsEngine(20, 20)
+	pe.Materials = NewMaterialGrid(20, 20)
+	pe.SetGravityAngle(180) // Gravity pulls left
+	pe.Gravity = 0
+	pe.Materials.Set(5, 5, MaterialStone)
+
+	sphere := NewSphere(5.5, 5.5, 1, lipgloss.Color("32"))
+	pe.ApplyPhysics([]Entity{sphere})
+
+	if x, _ := sphere.GetPosition(); int(x) != 6 {
+		t.Errorf("Expected sphere buried in stone to be pushed right, got x=%.2f", x)
+	}
+}
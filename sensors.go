@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SensorEventKind describes how an entity relates to a sensor this tick
+type SensorEventKind int
+
+const (
+	SensorEnter SensorEventKind = iota // Entity moved into the zone
+	SensorStay                         // Entity was already inside and still is
+	SensorExit                         // Entity left the zone or was removed
+)
+
+func (k SensorEventKind) String() string {
+	switch k {
+	case SensorEnter:
+		return "enter"
+	case SensorStay:
+		return "stay"
+	case SensorExit:
+		return "exit"
+	}
+	return "unknown"
+}
+
+// SensorEvent is emitted to the Model when an entity interacts with a sensor
+type SensorEvent struct {
+	Kind   SensorEventKind
+	Sensor *Sensor
+	Entity Entity
+}
+
+// SensorActions are optional effects applied when an entity enters a sensor
+type SensorActions struct {
+	Recolor              lipgloss.Color // Empty leaves the color unchanged
+	Teleport             bool
+	TeleportX, TeleportY float64
+	Remove               bool
+	Score                int // Added to the model's score
+}
+
+// Sensor is a trigger zone that detects entities without any collision response
+type Sensor struct {
+	ID                  string
+	X, Y, Width, Height float64
+	Mask                CollisionLayer // Entity categories that trigger the sensor
+	OnEnter             SensorActions
+	Color               lipgloss.Color
+
+	inside map[string]Entity // Entities inside as of the last update, by ID
+}
+
+// NewSensor creates a sensor covering the rectangle at (x, y) with the given size
+func NewSensor(x, y, width, height float64) *Sensor {
+	return &Sensor{
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+		Mask:   LayerAll,
+		Color:  lipgloss.Color("#FFD700"),
+		inside: make(map[string]Entity),
+	}
+}
+
+// Contains reports whether an entity's position lies inside the zone
+// and its category is accepted by the sensor mask
+func (s *Sensor) Contains(entity Entity) bool {
+	category, _ := collisionLayersOf(entity)
+	if category&s.Mask == 0 {
+		return false
+	}
+	x, y := entity.GetPosition()
+	return x >= s.X && x < s.X+s.Width && y >= s.Y && y < s.Y+s.Height
+}
+
+// Count returns the number of entities currently inside the zone
+func (s *Sensor) Count() int {
+	return len(s.inside)
+}
+
+// SensorManager owns every sensor in the simulation
+type SensorManager struct {
+	sensors []*Sensor
+	nextID  int
+}
+
+// NewSensorManager creates an empty sensor manager
+func NewSensorManager() *SensorManager {
+	return &SensorManager{nextID: 1}
+}
+
+// Add registers a sensor, assigning an ID if it has none
+func (sm *SensorManager) Add(sensor *Sensor) {
+	if sensor.ID == "" {
+		sensor.ID = fmt.Sprintf("sensor_%d", sm.nextID)
+		sm.nextID++
+	}
+	if sensor.inside == nil {
+		sensor.inside = make(map[string]Entity)
+	}
+	sm.sensors = append(sm.sensors, sensor)
+}
+
+// Sensors returns the registered sensors
+func (sm *SensorManager) Sensors() []*Sensor {
+	return sm.sensors
+}
+
+// Clear removes every sensor
+func (sm *SensorManager) Clear() {
+	sm.sensors = nil
+}
+
+// Update tests the entities the query finds over each sensor and returns the resulting
+// events. Entities that disappeared since the last update produce exit events.
+func (sm *SensorManager) Update(query rectQuery) []SensorEvent {
+	var events []SensorEvent
+
+	for _, sensor := range sm.sensors {
+		present := make(map[string]Entity, len(sensor.inside))
+		for _, entity := range query(sensor.X, sensor.Y, sensor.X+sensor.Width, sensor.Y+sensor.Height) {
+			if !sensor.Contains(entity) {
+				continue
+			}
+			id := entity.GetID()
+			present[id] = entity
+			kind := SensorEnter
+			if _, wasInside := sensor.inside[id]; wasInside {
+				kind = SensorStay
+			}
+			events = append(events, SensorEvent{Kind: kind, Sensor: sensor, Entity: entity})
+		}
+
+		for id, entity := range sensor.inside {
+			if _, stillInside := present[id]; !stillInside {
+				events = append(events, SensorEvent{Kind: SensorExit, Sensor: sensor, Entity: entity})
+			}
+		}
+
+		sensor.inside = present
+	}
+
+	return events
+}
+
+// sensorPreset is a kind of zone that can be placed from the keyboard
+type sensorPreset struct {
+	Name  string
+	Color lipgloss.Color
+}
+
+// sensorPresets lists the zone kinds cycled with the j key
+var sensorPresets = []sensorPreset{
+	{Name: "Goal", Color: lipgloss.Color("#FFD700")},
+	{Name: "Paint", Color: lipgloss.Color("#DA70D6")},
+	{Name: "Portal", Color: lipgloss.Color("#00CED1")},
+	{Name: "Drain", Color: lipgloss.Color("#FF4500")},
+}
+
+// newPresetSensor creates a zone of the given preset. Portals send entities to (portalX, portalY).
+func newPresetSensor(preset int, x, y, width, height, portalX, portalY float64) *Sensor {
+	sensor := NewSensor(x, y, width, height)
+	sensor.Color = sensorPresets[preset].Color
+	switch sensorPresets[preset].Name {
+	case "Goal":
+		sensor.OnEnter.Score = 1
+	case "Paint":
+		sensor.OnEnter.Recolor = sensor.Color
+	case "Portal":
+		sensor.OnEnter.Teleport = true
+		sensor.OnEnter.TeleportX, sensor.OnEnter.TeleportY = portalX, portalY
+	case "Drain":
+		sensor.OnEnter.Remove = true
+		sensor.OnEnter.Score = 1
+	}
+	return sensor
+}
+
+// Render returns the cell for the zone outline at (x, y), or an empty cell if it
+// is not on the outline. The top-left corner shows the occupancy count.
+func (s *Sensor) Render(x, y int) Cell {
+	left, top := int(s.X), int(s.Y)
+	right, bottom := int(s.X+s.Width)-1, int(s.Y+s.Height)-1
+	if x < left || x > right || y < top || y > bottom {
+		return Cell{}
+	}
+
+	cell := Cell{Color: s.Color}
+	switch {
+	case x == left && y == top:
+		if count := s.Count(); count > 0 && count < 10 {
+			cell.Glyph, cell.Bold = fmt.Sprintf("%d", count), true
+		} else if count >= 10 {
+			cell.Glyph, cell.Bold = "+", true
+		} else {
+			cell.Glyph = "┌"
+		}
+	case x == right && y == top:
+		cell.Glyph = "┐"
+	case x == left && y == bottom:
+		cell.Glyph = "└"
+	case x == right && y == bottom:
+		cell.Glyph = "┘"
+	case y == top || y == bottom:
+		cell.Glyph = "┄"
+	case x == left || x == right:
+		cell.Glyph = "┆"
+	}
+	return cell
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSensorEnterStayExit(t *testing.T) {
+	sm := NewSensorManager()
+	sensor := NewSensor(10, 10, 5, 5)
+	sm.Add(sensor)
+
+	sphere := NewSphere(5.0, 12.0, 1, lipgloss.Color("32"))
+	entities := []Entity{sphere}
+
+	if events := sm.Update(hashQuery(entities)); len(events) != 0 {
+		t.Fatalf("Expected no events while outside, got %d", len(events))
+	}
+
+	sphere.SetImmediatePosition(12.0, 12.0)
+	events := sm.Update(hashQuery(entities))
+	if len(events) != 1 || events[0].Kind != SensorEnter {
+		t.Fatalf("Expected one enter event, got %v", events)
+	}
+	if sensor.Count() != 1 {
+		t.Errorf("Expected 1 entity inside, got %d", sensor.Count())
+	}
+
+	events = sm.Update(hashQuery(entities))
+	if len(events) != 1 || events[0].Kind != SensorStay {
+		t.Fatalf("Expected one stay event, got %v", events)
+	}
+
+	sphere.SetImmediatePosition(20.0, 12.0)
+	events = sm.Update(hashQuery(entities))
+	if len(events) != 1 || events[0].Kind != SensorExit {
+		t.Fatalf("Expected one exit event, got %v", events)
+	}
+	if sensor.Count() != 0 {
+		t.Errorf("Expected sensor to be empty, got %d", sensor.Count())
+	}
+}
+
+func TestSensorExitOnRemoval(t *testing.T) {
+	sm := NewSensorManager()
+	sm.Add(NewSensor(0, 0, 10, 10))
+	sphere := NewSphere(5.0, 5.0, 1, lipgloss.Color("32"))
+
+	sm.Update(hashQuery([]Entity{sphere}))
+	events := sm.Update(hashQuery(nil))
+	if len(events) != 1 || events[0].Kind != SensorExit || events[0].Entity != Entity(sphere) {
+		t.Errorf("Expected an exit event for the removed entity, got %v", events)
+	}
+}
+
+func TestSensorHasNoCollisionResponse(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	pe.Gravity = 0
+	sm := NewSensorManager()
+	sm.Add(NewSensor(10, 0, 5, 50))
+
+	sphere := NewSphere(9.5, 10.0, 1, lipgloss.Color("32"))
+	sphere.SetVelocity(10.0, 0)
+
+	pe.ApplyPhysics([]Entity{sphere})
+	sm.Update(hashQuery([]Entity{sphere}))
+
+	if vx, _ := sphere.GetVelocity(); vx <= 0 {
+		t.Errorf("Expected sphere to pass into the sensor unaffected, got vx=%.2f", vx)
+	}
+}
+
+func TestSensorMask(t *testing.T) {
+	sensor := NewSensor(0, 0, 10, 10)
+	sensor.Mask = LayerDefault
+
+	ghost := NewSprite(5.0, 5.0, 1, lipgloss.Color("31"), "★")
+	ghost.MakeGhost()
+
+	if sensor.Contains(ghost) {
+		t.Error("Expected sensor to ignore categories outside its mask")
+	}
+}
+
+func TestModelSensorActions(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	model = updatedModel.(Model)
+
+	goal := NewSensor(10, 5, 5, 5)
+	goal.OnEnter.Score = 3
+	goal.OnEnter.Recolor = lipgloss.Color("#123456")
+	model.sensors.Add(goal)
+
+	drain := NewSensor(30, 5, 5, 5)
+	drain.OnEnter.Remove = true
+	model.sensors.Add(drain)
+
+	portal := NewSensor(50, 5, 5, 5)
+	portal.OnEnter.Teleport = true
+	portal.OnEnter.TeleportX, portal.OnEnter.TeleportY = 5, 2
+	model.sensors.Add(portal)
+
+	scored := NewSphere(12.0, 7.0, 1, lipgloss.Color("32"))
+	drained := NewSphere(32.0, 7.0, 1, lipgloss.Color("32"))
+	teleported := NewSphere(52.0, 7.0, 1, lipgloss.Color("32"))
+	model.entityManager.AddEntity(scored)
+	model.entityManager.AddEntity(drained)
+	model.entityManager.AddEntity(teleported)
+
+	model.physicsEngine.Pause() // Keep entities still; sensors still run on tick
+	model.physicsEngine.DeltaTime = 0
+	updatedModel, _ = model.Update(tickMsg(time.Now()))
+	model = updatedModel.(Model)
+
+	if model.score != 3 {
+		t.Errorf("Expected score 3, got %d", model.score)
+	}
+	if scored.GetColor() != lipgloss.Color("#123456") {
+		t.Errorf("Expected entity to be recolored, got %s", scored.GetColor())
+	}
+	if model.entityManager.Count() != 2 {
+		t.Errorf("Expected drained entity to be removed, got %d entities", model.entityManager.Count())
+	}
+	if x, y := teleported.GetPosition(); x != 5 || y != 2 {
+		t.Errorf("Expected entity to be teleported to (5, 2), got (%.1f, %.1f)", x, y)
+	}
+
+	model.renderer = NewCellBuffer()
+	if !strings.Contains(model.renderSimulation(), "Score: 3") {
+		t.Error("Expected score to be shown in the status line")
+	}
+}
+
+func TestModelPlaceSensor(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model = updatedModel.(Model)
+	model.brushX, model.brushY = 20, 5
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	model = updatedModel.(Model)
+
+	if len(model.sensors.Sensors()) != 1 {
+		t.Fatalf("Expected 1 sensor after k, got %d", len(model.sensors.Sensors()))
+	}
+	if model.sensors.Sensors()[0].OnEnter.Score != 1 {
+		t.Error("Expected the default zone to be a scoring goal")
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	model = updatedModel.(Model)
+	if len(model.sensors.Sensors()) != 0 {
+		t.Error("Expected K to remove all sensors")
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import "math"
+
+// ShapeKind is the outline a multi-cell entity is drawn and collides with
+type ShapeKind int
+
+const (
+	ShapeEllipse ShapeKind = iota // Rounded body; collides as an ellipse
+	ShapeBox                      // Framed box; collides as a rectangle
+)
+
+// multiCellMinSize is the smallest entity size drawn with more than one cell.
+// Smaller entities draw a single glyph and collide as circles.
+const multiCellMinSize = 3
+
+// shapeCells returns the width and height in cells of an entity of the given size.
+// Terminal cells are about twice as tall as they are wide, so shapes are wider than tall:
+// size 3 is 3×2, size 4 is 5×3, size 5 is 7×4 and so on.
+func shapeCells(size int) (width, height int) {
+	if size < multiCellMinSize {
+		return 1, 1
+	}
+	return 2*size - 3, size - 1
+}
+
+// isMultiCell reports whether an entity of the given size is drawn with several cells
+func isMultiCell(size int) bool {
+	return size >= multiCellMinSize
+}
+
+// shapeKindOf returns the registered shape of an entity's type, defaulting to an ellipse
+func shapeKindOf(entity Entity) ShapeKind {
+	if info, ok := LookupEntityType(entity.GetType()); ok {
+		return info.Shape
+	}
+	return ShapeEllipse
+}
+
+// multiCellBounds returns the bounds of the shape drawn for a multi-cell entity at (x, y)
+func multiCellBounds(x, y float64, size int) (left, top, width, height float64) {
+	w, h := shapeCells(size)
+	width, height = float64(w), float64(h)
+	return x - width/2, y - height/2, width, height
+}
+
+// multiCellRenderer is implemented by entities that draw more than one cell
+type multiCellRenderer interface {
+	RenderCells() [][]Cell
+}
+
+// shapeMask returns which cells of a multi-cell entity's shape are drawn, or nil for
+// a single-cell entity
+func shapeMask(entity Entity) [][]bool {
+	if !isMultiCell(entity.GetSize()) {
+		return nil
+	}
+	width, height := shapeCells(entity.GetSize())
+	rows := ellipseShape(width, height)
+	if shapeKindOf(entity) == ShapeBox {
+		rows = boxShape(width, height, entity.GetSymbol())
+	}
+	mask := make([][]bool, height)
+	for y, row := range rows {
+		mask[y] = make([]bool, width)
+		for x, cell := range row {
+			mask[y][x] = cell != ""
+		}
+	}
+	return mask
+}
+
+// drawnOrigin returns the top-left cell of a width×height shape centered on (x, y)
+func drawnOrigin(x, y float64, width, height int) (left, top int) {
+	return int(math.Floor(x - float64(width)/2 + 0.5)), int(math.Floor(y - float64(height)/2 + 0.5))
+}
+
+// RenderCells draws the entity as rows of cells; empty cells are transparent.
+// Single-cell entities return their glyph from Render.
+func (e *BaseEntity) RenderCells() [][]Cell {
+	if !isMultiCell(e.Size) {
+		return [][]Cell{{e.Render()}}
+	}
+
+	width, height := shapeCells(e.Size)
+	kind := ShapeEllipse
+	if info, ok := LookupEntityType(e.Type); ok {
+		kind = info.Shape
+	}
+
+	var rows [][]string
+	if kind == ShapeBox {
+		rows = boxShape(width, height, e.Symbol)
+	} else {
+		rows = ellipseShape(width, height)
+	}
+	cells := make([][]Cell, len(rows))
+	for y, row := range rows {
+		cells[y] = make([]Cell, len(row))
+		for x, glyph := range row {
+			if glyph != "" {
+				cells[y][x] = Cell{Glyph: glyph, Color: e.Color, Bold: true, Faint: e.IsGhost()}
+			}
+		}
+	}
+	return cells
+}
+
+// ellipseShape fills the cells whose centers lie inside the ellipse inscribed in
+// width×height, rounding the ends of the top and bottom rows with half blocks
+func ellipseShape(width, height int) [][]string {
+	a, b := float64(width)/2, float64(height)/2
+	rows := make([][]string, height)
+	for y := range rows {
+		rows[y] = make([]string, width)
+		dy := (float64(y) + 0.5 - b) / b
+		first, last := -1, -1
+		for x := range rows[y] {
+			dx := (float64(x) + 0.5 - a) / a
+			if dx*dx+dy*dy <= 1 {
+				rows[y][x] = "█"
+				if first < 0 {
+					first = x
+				}
+				last = x
+			}
+		}
+		if first < 0 || (y != 0 && y != height-1) {
+			continue
+		}
+		edge := "▄" // Top row: lower half blocks
+		if y == height-1 && y != 0 {
+			edge = "▀"
+		}
+		rows[y][first], rows[y][last] = edge, edge
+	}
+	return rows
+}
+
+// boxShape draws a framed box with the symbol in the middle of the top half
+func boxShape(width, height int, symbol string) [][]string {
+	rows := make([][]string, height)
+	for y := range rows {
+		rows[y] = make([]string, width)
+		for x := range rows[y] {
+			top, bottom := y == 0, y == height-1
+			left, right := x == 0, x == width-1
+			switch {
+			case top && left:
+				rows[y][x] = "┌"
+			case top && right:
+				rows[y][x] = "┐"
+			case bottom && left:
+				rows[y][x] = "└"
+			case bottom && right:
+				rows[y][x] = "┘"
+			case top || bottom:
+				rows[y][x] = "─"
+			case left || right:
+				rows[y][x] = "│"
+			default:
+				rows[y][x] = " "
+			}
+		}
+	}
+	rows[(height-1)/2][width/2] = symbol
+	return rows
+}
+
+// collisionExtent returns the distance from an entity's center to the edge of its
+// collision shape in the unit direction (nx, ny). Single-cell entities are circles.
+func collisionExtent(entity Entity, nx, ny float64) float64 {
+	_, _, w, h := entity.GetBounds()
+	return shapeExtent(shapeKindOf(entity), w/2, h/2, nx, ny)
+}
+
+// shapeExtent is collisionExtent for a shape with half extents a and b
+func shapeExtent(kind ShapeKind, a, b, nx, ny float64) float64 {
+	if a == b {
+		return a
+	}
+
+	if kind == ShapeBox {
+		extent := math.Inf(1)
+		if nx != 0 {
+			extent = a / math.Abs(nx)
+		}
+		if ny != 0 {
+			extent = math.Min(extent, b/math.Abs(ny))
+		}
+		return extent
+	}
+
+	// Radius of an ellipse with semi-axes a and b along the direction
+	return a * b / math.Hypot(b*nx, a*ny)
+}
+
+// boundaryExtents returns the half width and height kept inside the walls.
+// Single-cell entities keep half their size; multi-cell entities their drawn shape.
+func boundaryExtents(entity Entity) (halfWidth, halfHeight float64) {
+	if isMultiCell(entity.GetSize()) {
+		_, _, w, h := entity.GetBounds()
+		return w / 2, h / 2
+	}
+	size := float64(entity.GetSize())
+	return size / 2, size / 2
+}
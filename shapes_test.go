@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestShapeCells(t *testing.T) {
+	tests := []struct {
+		size, width, height int
+	}{
+		{1, 1, 1},
+		{2, 1, 1},
+		{3, 3, 2},
+		{4, 5, 3},
+		{5, 7, 4},
+	}
+	for _, tt := range tests {
+		if w, h := shapeCells(tt.size); w != tt.width || h != tt.height {
+			t.Errorf("size %d: expected %dx%d cells, got %dx%d", tt.size, tt.width, tt.height, w, h)
+		}
+	}
+}
+
+func TestRenderCellsMatchesBounds(t *testing.T) {
+	entities := []Entity{
+		NewSphere(20, 10, 4, lipgloss.Color("32")),
+		NewSprite(20, 10, 4, lipgloss.Color("31"), "★"),
+		NewBoid(20, 10, 4, lipgloss.Color("33")),
+	}
+	for _, entity := range entities {
+		cells := entity.(multiCellRenderer).RenderCells()
+		_, _, w, h := entity.GetBounds()
+		if len(cells) != int(h) || len(cells[0]) != int(w) {
+			t.Errorf("%s: expected drawn shape to match %vx%v bounds, got %dx%d",
+				entity.GetType(), w, h, len(cells[0]), len(cells))
+		}
+	}
+
+	// The sprite is framed with its symbol inside
+	sprite := NewSprite(20, 10, 4, lipgloss.Color("31"), "★")
+	var drawn []string
+	for _, row := range sprite.RenderCells() {
+		var line strings.Builder
+		for _, cell := range row {
+			line.WriteString(cell.Glyph)
+		}
+		drawn = append(drawn, line.String())
+	}
+	if drawn[0] != "┌───┐" || drawn[1] != "│ ★ │" || drawn[2] != "└───┘" {
+		t.Errorf("Expected a framed sprite, got %q", drawn)
+	}
+
+	// Small entities still draw a single glyph
+	if cells := NewSphere(5, 5, 2, lipgloss.Color("32")).RenderCells(); len(cells) != 1 || len(cells[0]) != 1 {
+		t.Error("Expected small entities to draw one cell")
+	}
+}
+
+func TestLargeEntityStaysInsideWalls(t *testing.T) {
+	pe := NewPhysicsEngine(40, 20)
+	pe.Gravity = 0
+	sphere := NewSphere(1, 1, 5, lipgloss.Color("32"))
+	sphere.SetVelocity(-5, -5)
+
+	pe.ApplyPhysics([]Entity{sphere})
+
+	x, y := sphere.GetPosition()
+	_, _, w, h := sphere.GetBounds()
+	if x-w/2 < pe.MinX || y-h/2 < pe.MinY {
+		t.Errorf("Expected the whole shape inside the walls, got center (%.2f, %.2f) for %vx%v", x, y, w, h)
+	}
+}
+
+func TestLargeEntityCollisionUsesShape(t *testing.T) {
+	pe := NewPhysicsEngine(80, 40)
+	wide := NewSphere(20, 10, 5, lipgloss.Color("32")) // 7x4 ellipse
+
+	// A neighbour beside the wide shape is touching it, one the same distance below is not
+	beside := NewSphere(23, 10, 1, lipgloss.Color("31"))
+	below := NewSphere(20, 13, 1, lipgloss.Color("31"))
+	if !pe.checkEntityCollision(wide, beside) {
+		t.Error("Expected an entity within the shape's half width to collide")
+	}
+	if pe.checkEntityCollision(wide, below) {
+		t.Error("Expected an entity beyond the shape's half height not to collide")
+	}
+
+	// Box corners reach further than the ellipse
+	box := NewSprite(20, 10, 5, lipgloss.Color("31"), "★")
+	diagonal := math.Sqrt(0.5)
+	if collisionExtent(box, diagonal, diagonal) <= collisionExtent(wide, diagonal, diagonal) {
+		t.Error("Expected a box to extend further diagonally than an ellipse")
+	}
+}
+
+func TestLargeEntityDrawnAcrossCells(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+
+	sprite := NewSprite(20, 10, 4, lipgloss.Color("31"), "★")
+	model.entityManager.AddEntity(sprite)
+
+	model.renderer = NewCellBuffer()
+	view := model.renderSimulation()
+	for _, row := range []string{"┌───┐", "│ ★ │", "└───┘"} {
+		if !strings.Contains(view, row) {
+			t.Errorf("Expected the view to contain %q", row)
+		}
+	}
+
+	// Picking a cell on the frame, away from the center, finds the sprite
+	entities := model.entityManager.GetEntities()
+	if entity, ok := pickGlyph(model.camera, entities, 18, 9, OverlapTopmost); !ok || entity != Entity(sprite) {
+		t.Error("Expected picking to match anywhere in the drawn footprint")
+	}
+	if _, ok := pickGlyph(model.camera, entities, 23, 10, OverlapTopmost); ok {
+		t.Error("Expected cells beside the footprint to be empty")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSlingshotLaunchVelocity(t *testing.T) {
+	var s Slingshot
+	s.Begin(10, 10)
+	s.Drag(7, 12)
+
+	vx, vy := s.LaunchVelocity()
+	if vx != 3*SlingshotStrength || vy != -2*SlingshotStrength {
+		t.Errorf("Expected launch opposite and proportional to the drag, got (%.1f, %.1f)", vx, vy)
+	}
+
+	s.Cancel()
+	s.Drag(0, 0)
+	if s.PullX != 7 || s.PullY != 12 {
+		t.Error("Expected drag to be ignored when not aiming")
+	}
+}
+
+func TestLineCells(t *testing.T) {
+	cells := lineCells(0, 0, 4, 2)
+	if len(cells) != 5 {
+		t.Fatalf("Expected 5 cells, got %d", len(cells))
+	}
+	if cells[0] != [2]int{0, 0} || cells[len(cells)-1] != [2]int{4, 2} {
+		t.Errorf("Expected line to run between its endpoints, got %v", cells)
+	}
+
+	if cells := lineCells(3, 3, 3, 3); len(cells) != 1 {
+		t.Errorf("Expected a single cell for a zero-length line, got %v", cells)
+	}
+}
+
+func TestPredictTrajectory(t *testing.T) {
+	pe := NewPhysicsEngine(100, 50)
+	pe.Pause()
+
+	probe := NewSphere(10.0, 40.0, 1, lipgloss.Color("32"))
+	probe.SetVelocity(20.0, -30.0)
+	path := pe.PredictTrajectory(probe, 20)
+
+	if len(path) == 0 {
+		t.Fatal("Expected a predicted path while paused")
+	}
+	if pe.DeltaTime != 0 {
+		t.Error("Expected prediction to leave the time step unchanged")
+	}
+
+	// The path rises, then gravity bends it back down
+	peak := path[0][1]
+	for _, point := range path {
+		peak = math.Min(peak, point[1])
+		if point[0] < pe.MinX || point[0] > pe.MaxX || point[1] < pe.MinY || point[1] > pe.MaxY {
+			t.Fatalf("Predicted point (%.1f, %.1f) is outside the bounds", point[0], point[1])
+		}
+	}
+	if peak >= 40.0 || path[len(path)-1][1] <= peak {
+		t.Errorf("Expected an arc under gravity, peak %.1f end %.1f", peak, path[len(path)-1][1])
+	}
+}
+
+func TestModelSlingshotLaunch(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model = updatedModel.(Model)
+	model.selectedEntityType = SpriteType
+
+	mouse := func(x, y int, button tea.MouseButton, action tea.MouseAction) {
+		updatedModel, _ := model.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: action})
+		model = updatedModel.(Model)
+	}
+
+	// Anchor at grid cell (20, 6), then pull back down and to the left
+	mouse(SimGridOriginX+20, SimGridOriginY+6, tea.MouseButtonLeft, tea.MouseActionPress)
+	mouse(SimGridOriginX+16, SimGridOriginY+8, tea.MouseButtonLeft, tea.MouseActionMotion)
+
+	if !model.slingshot.Aiming {
+		t.Fatal("Expected a press in the simulation to start aiming")
+	}
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	model.renderSimulation()
+	if buffer.LayerGlyph(LayerOverlay, 20, 6) != "•" || buffer.Count("·") == 0 {
+		t.Error("Expected the rubber band and predicted path to be drawn while aiming")
+	}
+	if model.entityManager.Count() != 0 {
+		t.Error("Expected no entity before release")
+	}
+
+	mouse(SimGridOriginX+16, SimGridOriginY+8, tea.MouseButtonNone, tea.MouseActionRelease)
+
+	if model.slingshot.Aiming {
+		t.Error("Expected release to stop aiming")
+	}
+	if model.entityManager.Count() != 1 {
+		t.Fatalf("Expected release to launch one entity, got %d", model.entityManager.Count())
+	}
+	entity := model.entityManager.GetEntities()[0]
+	if entity.GetType() != SpriteType || entity.GetSize() != model.selectedEntitySize {
+		t.Errorf("Expected launched entity to use the selected type and size, got %s size %d", entity.GetType(), entity.GetSize())
+	}
+	if x, y := entity.GetPosition(); x != 20.5 || y != 6.5 {
+		t.Errorf("Expected entity to launch from the anchor, got (%.1f, %.1f)", x, y)
+	}
+	if vx, vy := entity.GetVelocity(); vx <= 0 || vy >= 0 {
+		t.Errorf("Expected launch up and to the right, got (%.1f, %.1f)", vx, vy)
+	}
+}
+
+func TestCycleEntityType(t *testing.T) {
+	model := initialModel()
+	for _, expected := range []EntityType{SpriteType, BoidType, SphereType} {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+		model = updatedModel.(Model)
+		if model.selectedEntityType != expected {
+			t.Errorf("Expected %s, got %s", expected, model.selectedEntityType)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// spatialCellSize is the side of a spatial hash bucket in simulation cells
+const spatialCellSize = 4.0
+
+// spatialKey identifies a bucket of the spatial hash
+type spatialKey struct{ X, Y int }
+
+// spatialHash buckets entities by the grid cells their bounds overlap.
+// It is the acceleration structure behind the EntityManager query methods.
+type spatialHash struct {
+	buckets map[spatialKey][]Entity
+
+	// Range of occupied buckets, used to stop searches that find nothing
+	minKey, maxKey spatialKey
+}
+
+// newSpatialHash builds a spatial hash over the given entities
+func newSpatialHash(entities []Entity) *spatialHash {
+	h := &spatialHash{buckets: make(map[spatialKey][]Entity)}
+	first := true
+	for _, entity := range entities {
+		x, y, w, hgt := entity.GetBounds()
+		lo, hi := spatialKeyAt(x, y), spatialKeyAt(x+w, y+hgt)
+		for ky := lo.Y; ky <= hi.Y; ky++ {
+			for kx := lo.X; kx <= hi.X; kx++ {
+				key := spatialKey{kx, ky}
+				h.buckets[key] = append(h.buckets[key], entity)
+			}
+		}
+		if first {
+			h.minKey, h.maxKey = lo, hi
+			first = false
+		}
+		h.minKey = spatialKey{min(h.minKey.X, lo.X), min(h.minKey.Y, lo.Y)}
+		h.maxKey = spatialKey{max(h.maxKey.X, hi.X), max(h.maxKey.Y, hi.Y)}
+	}
+	return h
+}
+
+// spatialKeyAt returns the bucket containing a point
+func spatialKeyAt(x, y float64) spatialKey {
+	return spatialKey{int(math.Floor(x / spatialCellSize)), int(math.Floor(y / spatialCellSize))}
+}
+
+// candidates returns the distinct entities in buckets overlapping the rectangle
+func (h *spatialHash) candidates(minX, minY, maxX, maxY float64) []Entity {
+	lo, hi := spatialKeyAt(minX, minY), spatialKeyAt(maxX, maxY)
+	lo = spatialKey{max(lo.X, h.minKey.X), max(lo.Y, h.minKey.Y)}
+	hi = spatialKey{min(hi.X, h.maxKey.X), min(hi.Y, h.maxKey.Y)}
+
+	var result []Entity
+	seen := make(map[Entity]bool)
+	for ky := lo.Y; ky <= hi.Y; ky++ {
+		for kx := lo.X; kx <= hi.X; kx++ {
+			for _, entity := range h.buckets[spatialKey{kx, ky}] {
+				if !seen[entity] {
+					seen[entity] = true
+					result = append(result, entity)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// RebuildSpatialIndex re-buckets every entity at its current position (thread-safe).
+// The simulation calls it once per tick after physics, before sensors query it, and
+// behaviors query it on the next tick through World.QueryRadius; adding or removing entities
+// rebuilds it automatically on the next query. Call it after moving entities by hand.
+func (em *EntityManager) RebuildSpatialIndex() {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.spatial = newSpatialHash(em.entities)
+}
+
+// spatialIndex returns the spatial hash, rebuilding it if entities were added or removed.
+// The caller must not hold the lock.
+func (em *EntityManager) spatialIndex() *spatialHash {
+	em.mu.RLock()
+	index := em.spatial
+	em.mu.RUnlock()
+	if index != nil {
+		return index
+	}
+
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if em.spatial == nil {
+		em.spatial = newSpatialHash(em.entities)
+	}
+	return em.spatial
+}
+
+// rectQuery finds the entities whose bounds overlap a rectangle
+type rectQuery func(minX, minY, maxX, maxY float64) []Entity
+
+// QueryRect returns the entities whose bounds overlap the rectangle (thread-safe)
+func (em *EntityManager) QueryRect(minX, minY, maxX, maxY float64) []Entity {
+	index := em.spatialIndex()
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return queryRect(index, minX, minY, maxX, maxY)
+}
+
+// queryRect is QueryRect without locking
+func queryRect(index *spatialHash, minX, minY, maxX, maxY float64) []Entity {
+	var result []Entity
+	for _, entity := range index.candidates(minX, minY, maxX, maxY) {
+		x, y, w, h := entity.GetBounds()
+		if x <= maxX && x+w >= minX && y <= maxY && y+h >= minY {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// hashQuery indexes a fixed set of entities, for callers that have no EntityManager
+func hashQuery(entities []Entity) rectQuery {
+	index := newSpatialHash(entities)
+	return func(minX, minY, maxX, maxY float64) []Entity {
+		return queryRect(index, minX, minY, maxX, maxY)
+	}
+}
+
+// QueryRadius returns the entities whose centers lie within radius of (x, y) (thread-safe)
+func (em *EntityManager) QueryRadius(x, y, radius float64) []Entity {
+	index := em.spatialIndex()
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return queryRadius(index, x, y, radius)
+}
+
+// queryRadius is QueryRadius without locking
+func queryRadius(index *spatialHash, x, y, radius float64) []Entity {
+	var result []Entity
+	for _, entity := range index.candidates(x-radius, y-radius, x+radius, y+radius) {
+		ex, ey := entity.GetPosition()
+		if math.Hypot(ex-x, ey-y) <= radius {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// Nearest returns up to k entities closest to (x, y), nearest first (thread-safe)
+func (em *EntityManager) Nearest(x, y float64, k int) []Entity {
+	if k <= 0 {
+		return nil
+	}
+	index := em.spatialIndex()
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	if len(index.buckets) == 0 {
+		return nil
+	}
+
+	// Grow the search radius until it holds k entities or covers every bucket
+	farthest := 0.0
+	for _, corner := range [][2]int{{index.minKey.X, index.minKey.Y}, {index.maxKey.X + 1, index.maxKey.Y + 1},
+		{index.minKey.X, index.maxKey.Y + 1}, {index.maxKey.X + 1, index.minKey.Y}} {
+		farthest = math.Max(farthest, math.Hypot(float64(corner[0])*spatialCellSize-x, float64(corner[1])*spatialCellSize-y))
+	}
+	var found []Entity
+	for radius := spatialCellSize; ; radius *= 2 {
+		found = queryRadius(index, x, y, radius)
+		if len(found) >= k || radius >= farthest {
+			break
+		}
+	}
+
+	distance := func(e Entity) float64 {
+		ex, ey := e.GetPosition()
+		return math.Hypot(ex-x, ey-y)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return distance(found[i]) < distance(found[j]) })
+	if len(found) > k {
+		found = found[:k]
+	}
+	return found
+}
+
+// Raycast returns the first entity hit by a ray from (x, y) in direction (dx, dy)
+// within maxDistance, and the distance to the hit. Entities are treated as circles
+// with the diameter of their bounds (thread-safe).
+func (em *EntityManager) Raycast(x, y, dx, dy, maxDistance float64) (Entity, float64, bool) {
+	length := math.Hypot(dx, dy)
+	if length == 0 || maxDistance <= 0 {
+		return nil, 0, false
+	}
+	dx, dy = dx/length, dy/length
+
+	index := em.spatialIndex()
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	// Walk the buckets along the ray (Amanatides-Woo). An entity hit at distance t is
+	// in the bucket containing the hit point, so once the best hit lies inside the
+	// current bucket no later bucket can do better.
+	key := spatialKeyAt(x, y)
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+	boundaryDistance := func(pos, dir float64, cell, step int) float64 {
+		if dir == 0 {
+			return math.Inf(1)
+		}
+		edge := float64(cell) * spatialCellSize
+		if step > 0 {
+			edge += spatialCellSize
+		}
+		return (edge - pos) / dir
+	}
+	nextX := boundaryDistance(x, dx, key.X, stepX)
+	nextY := boundaryDistance(y, dy, key.Y, stepY)
+	deltaX, deltaY := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		deltaX = spatialCellSize / math.Abs(dx)
+	}
+	if dy != 0 {
+		deltaY = spatialCellSize / math.Abs(dy)
+	}
+
+	var hit Entity
+	best := math.Inf(1)
+	tested := make(map[Entity]bool)
+	for traveled := 0.0; traveled <= maxDistance; {
+		for _, entity := range index.buckets[key] {
+			if tested[entity] {
+				continue
+			}
+			tested[entity] = true
+			if t, ok := rayCircle(x, y, dx, dy, entity); ok && t < best && t <= maxDistance {
+				hit, best = entity, t
+			}
+		}
+
+		exit := math.Min(nextX, nextY)
+		if hit != nil && best <= exit {
+			break
+		}
+		if key.X < index.minKey.X && stepX < 0 || key.X > index.maxKey.X && stepX > 0 ||
+			key.Y < index.minKey.Y && stepY < 0 || key.Y > index.maxKey.Y && stepY > 0 {
+			break // Heading away from every occupied bucket
+		}
+		if nextX < nextY {
+			key.X += stepX
+			nextX += deltaX
+		} else {
+			key.Y += stepY
+			nextY += deltaY
+		}
+		traveled = exit
+	}
+
+	if hit == nil {
+		return nil, 0, false
+	}
+	return hit, best, true
+}
+
+// rayCircle returns the distance along a unit ray to where it enters an entity's circle
+func rayCircle(x, y, dx, dy float64, entity Entity) (float64, bool) {
+	ex, ey := entity.GetPosition()
+	_, _, w, _ := entity.GetBounds()
+	radius := w / 2
+
+	// Solve |o + t*d - c|² = r² for the smallest t >= 0
+	ox, oy := x-ex, y-ey
+	b := ox*dx + oy*dy
+	c := ox*ox + oy*oy - radius*radius
+	if c <= 0 {
+		return 0, true // Ray starts inside the entity
+	}
+	discriminant := b*b - c
+	if b > 0 || discriminant < 0 {
+		return 0, false
+	}
+	return -b - math.Sqrt(discriminant), true
+}
+
+// EntityAt returns the entity drawn at a world cell, for mouse picking (thread-safe).
+// It answers for the default view, one world cell per glyph: entities are drawn at
+// their animated display position, multi-cell entities match anywhere in their drawn
+// shape, and where several are drawn the topmost by z-order is returned.
+func (em *EntityManager) EntityAt(cellX, cellY int) (Entity, bool) {
+	return em.EntityShown(NewCamera(), RenderGlyphs, OverlapTopmost, cellX, cellY)
+}
+
+// EntityShown returns the entity a view shows in view cell (sx, sy) (thread-safe).
+// It picks the way the view is drawn: the same glyphs or dots, z-order and overlap
+// policy.
+func (em *EntityManager) EntityShown(cam Camera, mode RenderMode, policy OverlapPolicy, sx, sy int) (Entity, bool) {
+	left, top, width, height := cam.BlockAt(cam.ToWorld(sx, sy))
+
+	// Shapes reach past their bounds' cells and display positions trail physics,
+	// so look a bucket beyond the block
+	minX, minY := float64(left)-spatialCellSize, float64(top)-spatialCellSize
+	maxX, maxY := float64(left+width)+spatialCellSize, float64(top+height)+spatialCellSize
+
+	index := em.spatialIndex()
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	candidates := queryRect(index, minX, minY, maxX, maxY)
+	if mode == RenderGlyphs {
+		return pickGlyph(cam, candidates, sx, sy, policy)
+	}
+	return pickDot(cam, mode, candidates, sx, sy)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newSpatialTestManager(count int) *EntityManager {
+	rng := rand.New(rand.NewSource(1))
+	manager := NewEntityManager()
+	for i := 0; i < count; i++ {
+		manager.AddEntity(NewSphere(rng.Float64()*80, rng.Float64()*40, 1+rng.Intn(4), lipgloss.Color("32")))
+	}
+	return manager
+}
+
+func TestQueryRadiusMatchesScan(t *testing.T) {
+	manager := newSpatialTestManager(200)
+
+	for _, query := range [][3]float64{{10, 10, 5}, {40, 20, 12}, {79, 39, 3}, {-5, -5, 2}} {
+		expected := 0
+		for _, entity := range manager.GetEntities() {
+			x, y := entity.GetPosition()
+			if math.Hypot(x-query[0], y-query[1]) <= query[2] {
+				expected++
+			}
+		}
+		if got := len(manager.QueryRadius(query[0], query[1], query[2])); got != expected {
+			t.Errorf("QueryRadius(%v): expected %d entities, got %d", query, expected, got)
+		}
+	}
+}
+
+func TestQueryRect(t *testing.T) {
+	manager := NewEntityManager()
+	inside := NewSphere(5.0, 5.0, 1, lipgloss.Color("32"))
+	edge := NewSphere(10.2, 5.0, 1, lipgloss.Color("32")) // Bounds reach back into the rect
+	outside := NewSphere(20.0, 5.0, 1, lipgloss.Color("32"))
+	manager.AddEntity(inside)
+	manager.AddEntity(edge)
+	manager.AddEntity(outside)
+
+	found := manager.QueryRect(0, 0, 10, 10)
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 entities overlapping the rect, got %d", len(found))
+	}
+	for _, entity := range found {
+		if entity == Entity(outside) {
+			t.Error("Expected entity outside the rect to be excluded")
+		}
+	}
+}
+
+func TestQueryUsesRebuiltPositions(t *testing.T) {
+	manager := NewEntityManager()
+	sphere := NewSphere(5.0, 5.0, 1, lipgloss.Color("32"))
+	manager.AddEntity(sphere)
+	manager.QueryRadius(0, 0, 1) // Build the index
+
+	sphere.SetImmediatePosition(50.0, 30.0)
+	manager.RebuildSpatialIndex()
+
+	if len(manager.QueryRadius(50, 30, 1)) != 1 {
+		t.Error("Expected query to find the entity at its position after a rebuild")
+	}
+	if len(manager.QueryRadius(5, 5, 1)) != 0 {
+		t.Error("Expected the old position to be empty after a rebuild")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	manager := newSpatialTestManager(100)
+	far := NewSphere(500.0, 500.0, 1, lipgloss.Color("32"))
+	manager.AddEntity(far)
+
+	nearest := manager.Nearest(40, 20, 5)
+	if len(nearest) != 5 {
+		t.Fatalf("Expected 5 nearest entities, got %d", len(nearest))
+	}
+	for i := 1; i < len(nearest); i++ {
+		x1, y1 := nearest[i-1].GetPosition()
+		x2, y2 := nearest[i].GetPosition()
+		if math.Hypot(x1-40, y1-20) > math.Hypot(x2-40, y2-20) {
+			t.Error("Expected results ordered nearest first")
+		}
+	}
+
+	// A point far from everything still finds the closest entity
+	if nearest := manager.Nearest(600, 600, 1); len(nearest) != 1 || nearest[0] != Entity(far) {
+		t.Error("Expected Nearest to search outward until it finds an entity")
+	}
+	if all := manager.Nearest(0, 0, 1000); len(all) != manager.Count() {
+		t.Errorf("Expected k larger than the population to return everything, got %d", len(all))
+	}
+}
+
+func TestRaycast(t *testing.T) {
+	manager := NewEntityManager()
+	near := NewSphere(10.0, 5.0, 2, lipgloss.Color("32"))
+	far := NewSphere(30.0, 5.0, 2, lipgloss.Color("32"))
+	offAxis := NewSphere(20.0, 15.0, 2, lipgloss.Color("32"))
+	manager.AddEntity(far)
+	manager.AddEntity(offAxis)
+	manager.AddEntity(near)
+
+	hit, distance, ok := manager.Raycast(0, 5, 1, 0, 100)
+	if !ok || hit != Entity(near) {
+		t.Fatalf("Expected ray to hit the nearest entity first, got %v", hit)
+	}
+	if math.Abs(distance-9.5) > 1e-9 {
+		t.Errorf("Expected hit at the sphere's surface (9.5), got %.2f", distance)
+	}
+
+	if _, _, ok := manager.Raycast(0, 5, 1, 0, 5); ok {
+		t.Error("Expected no hit beyond the max distance")
+	}
+	if _, _, ok := manager.Raycast(0, 5, -1, 0, 100); ok {
+		t.Error("Expected no hit when pointing away from every entity")
+	}
+	if hit, _, ok := manager.Raycast(20, 0, 0, 1, 100); !ok || hit != Entity(offAxis) {
+		t.Error("Expected a vertical ray to hit the entity below it")
+	}
+}
+
+func TestEntityAt(t *testing.T) {
+	manager := NewEntityManager()
+	bottom := NewSphere(5.5, 5.5, 1, lipgloss.Color("32"))
+	top := NewSprite(5.5, 5.5, 1, lipgloss.Color("31"), "★")
+	framed := NewSprite(20, 10, 4, lipgloss.Color("31"), "★")
+	manager.AddEntity(bottom)
+	manager.AddEntity(top)
+	manager.AddEntity(framed)
+
+	if entity, ok := manager.EntityAt(5, 5); !ok || entity != Entity(top) {
+		t.Error("Expected EntityAt to return the entity drawn on top")
+	}
+	if _, ok := manager.EntityAt(6, 5); ok {
+		t.Error("Expected an empty cell to have no entity")
+	}
+	if entity, ok := manager.EntityAt(18, 9); !ok || entity != Entity(framed) {
+		t.Error("Expected EntityAt to match anywhere in a drawn shape")
+	}
+
+	// Zoomed out, one character shows a block and the overlap policy decides the pick
+	bottom.SetMass(10)
+	cam := NewCamera()
+	cam.Zoom = 4
+	if entity, ok := manager.EntityShown(cam, RenderGlyphs, OverlapHeaviest, 1, 1); !ok || entity != Entity(bottom) {
+		t.Error("Expected EntityShown to pick by the view's overlap policy")
+	}
+}
+
+func TestSpatialQueriesConcurrent(t *testing.T) {
+	manager := newSpatialTestManager(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				switch j % 5 {
+				case 0:
+					manager.QueryRadius(float64(i*10), 20, 8)
+				case 1:
+					manager.Nearest(float64(i*10), 20, 3)
+				case 4:
+					manager.EntityAt(i*10, 20)
+				case 2:
+					manager.AddEntity(NewSphere(float64(j), float64(i), 1, lipgloss.Color("32")))
+				case 3:
+					manager.RebuildSpatialIndex()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestBroadPhaseMatchesScan(t *testing.T) {
+	pe := NewPhysicsEngine(80, 40)
+	entities := newSpatialTestManager(200).GetEntities()
+
+	var expected []CollisionPair
+	for i := range entities {
+		for j := i + 1; j < len(entities); j++ {
+			if shouldCollide(entities[i], entities[j]) && pe.checkEntityCollision(entities[i], entities[j]) {
+				expected = append(expected, CollisionPair{Entity1: entities[i], Entity2: entities[j]})
+			}
+		}
+	}
+	got := pe.findCollisions(entities)
+	if len(got) != len(expected) || len(got) == 0 {
+		t.Fatalf("Expected %d collisions, got %d", len(expected), len(got))
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected pair %d in slice order, got a different pair", i)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AnimationMode controls what a clip does after its last frame
+type AnimationMode string
+
+const (
+	AnimationLoop     AnimationMode = "loop"     // Restart from the first frame
+	AnimationPingPong AnimationMode = "pingpong" // Play backwards, then forwards again
+	AnimationOnce     AnimationMode = "once"     // Hold the last frame
+)
+
+// SpriteState selects which clip an animated sprite plays
+type SpriteState string
+
+const (
+	SpriteIdle   SpriteState = "idle"
+	SpriteMoving SpriteState = "moving"
+	SpriteHit    SpriteState = "hit" // Played once after a collision
+)
+
+const (
+	// defaultFrameDuration is how long a frame shows when neither it nor its clip sets a duration
+	defaultFrameDuration = 0.15
+
+	// spriteMovingSpeed is the speed above which a sprite plays its moving clip
+	spriteMovingSpeed = 1.0
+)
+
+// AnimationFrame is one frame of a clip. An empty Color keeps the sprite's own color.
+type AnimationFrame struct {
+	Symbol   string
+	Color    lipgloss.Color
+	Duration float64 // Seconds
+}
+
+// AnimationClip is a timed sequence of frames
+type AnimationClip struct {
+	Mode   AnimationMode
+	Frames []AnimationFrame
+}
+
+// NewLoopClip builds a looping clip that shows each symbol for the same duration
+func NewLoopClip(symbols []string, duration float64) *AnimationClip {
+	clip := &AnimationClip{Mode: AnimationLoop}
+	for _, symbol := range symbols {
+		clip.Frames = append(clip.Frames, AnimationFrame{Symbol: symbol, Duration: duration})
+	}
+	return clip
+}
+
+// ClipPlayer plays the clip for a sprite's current state.
+// Clips are shared between sprites; each sprite has its own player.
+type ClipPlayer struct {
+	Clips map[SpriteState]*AnimationClip
+	State SpriteState
+
+	frame     int
+	direction int     // +1 or -1 while ping-ponging
+	elapsed   float64 // Time spent on the current frame
+	done      bool    // A one-shot clip reached its last frame
+}
+
+// NewClipPlayer creates a player starting on the idle clip
+func NewClipPlayer(clips map[SpriteState]*AnimationClip) *ClipPlayer {
+	p := &ClipPlayer{Clips: clips}
+	p.restart(SpriteIdle)
+	return p
+}
+
+// clip returns the clip for the current state, falling back to idle
+func (p *ClipPlayer) clip() *AnimationClip {
+	if clip, ok := p.Clips[p.State]; ok {
+		return clip
+	}
+	return p.Clips[SpriteIdle]
+}
+
+// restart switches to a state from its first frame
+func (p *ClipPlayer) restart(state SpriteState) {
+	p.State = state
+	p.frame, p.direction, p.elapsed, p.done = 0, 1, 0, false
+}
+
+// Play switches to a state's clip. Playing the current state does nothing.
+func (p *ClipPlayer) Play(state SpriteState) {
+	if state != p.State {
+		p.restart(state)
+	}
+}
+
+// Has reports whether the player has a clip for a state
+func (p *ClipPlayer) Has(state SpriteState) bool {
+	_, ok := p.Clips[state]
+	return ok
+}
+
+// Done reports whether a one-shot clip has finished
+func (p *ClipPlayer) Done() bool {
+	return p.done
+}
+
+// Frame returns the frame currently shown
+func (p *ClipPlayer) Frame() AnimationFrame {
+	clip := p.clip()
+	if clip == nil || len(clip.Frames) == 0 {
+		return AnimationFrame{}
+	}
+	return clip.Frames[p.frame]
+}
+
+// FrameIndex returns the index of the frame currently shown
+func (p *ClipPlayer) FrameIndex() int {
+	return p.frame
+}
+
+// Advance moves the clip forward by deltaTime seconds
+func (p *ClipPlayer) Advance(deltaTime float64) {
+	clip := p.clip()
+	if clip == nil || len(clip.Frames) < 2 || p.done {
+		return
+	}
+
+	p.elapsed += deltaTime
+	for !p.done {
+		duration := clip.Frames[p.frame].Duration
+		if duration <= 0 {
+			duration = defaultFrameDuration
+		}
+		if p.elapsed < duration {
+			return
+		}
+		p.elapsed -= duration
+		p.step(clip)
+	}
+}
+
+// step moves to the next frame according to the clip's mode
+func (p *ClipPlayer) step(clip *AnimationClip) {
+	last := len(clip.Frames) - 1
+	switch clip.Mode {
+	case AnimationOnce:
+		if p.frame < last {
+			p.frame++
+		}
+		if p.frame == last {
+			p.done = true
+		}
+	case AnimationPingPong:
+		p.frame += p.direction
+		if p.frame > last {
+			p.direction = -1
+			p.frame = last - 1
+		} else if p.frame < 0 {
+			p.direction = 1
+			p.frame = 1
+		}
+	default:
+		p.frame = (p.frame + 1) % len(clip.Frames)
+	}
+}
+
+// FrameSpec is the declarative description of a frame.
+// In a sprite sheet a frame may also be written as just its symbol string.
+type FrameSpec struct {
+	Symbol   string  `json:"symbol"`
+	Color    string  `json:"color,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// UnmarshalJSON accepts either a frame object or a bare symbol string
+func (f *FrameSpec) UnmarshalJSON(data []byte) error {
+	var symbol string
+	if err := json.Unmarshal(data, &symbol); err == nil {
+		*f = FrameSpec{Symbol: symbol}
+		return nil
+	}
+	type plain FrameSpec // Avoid recursing into this method
+	var spec plain
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return err
+	}
+	*f = FrameSpec(spec)
+	return nil
+}
+
+// ClipSpec is the declarative description of a clip.
+// FrameDuration applies to frames that do not set their own duration.
+type ClipSpec struct {
+	Mode          AnimationMode `json:"mode,omitempty"`
+	FrameDuration float64       `json:"frame_duration,omitempty"`
+	Frames        []FrameSpec   `json:"frames"`
+}
+
+// Build creates the runtime clip
+func (s ClipSpec) Build() (*AnimationClip, error) {
+	mode := s.Mode
+	if mode == "" {
+		mode = AnimationLoop
+	}
+	if mode != AnimationLoop && mode != AnimationPingPong && mode != AnimationOnce {
+		return nil, fmt.Errorf("unknown animation mode %q", s.Mode)
+	}
+	if len(s.Frames) == 0 {
+		return nil, fmt.Errorf("at least one frame is required")
+	}
+
+	clip := &AnimationClip{Mode: mode}
+	for i, frame := range s.Frames {
+		if frame.Symbol == "" {
+			return nil, fmt.Errorf("frame %d: symbol is required", i)
+		}
+		duration := frame.Duration
+		if duration <= 0 {
+			duration = s.FrameDuration
+		}
+		clip.Frames = append(clip.Frames, AnimationFrame{
+			Symbol:   frame.Symbol,
+			Color:    lipgloss.Color(frame.Color),
+			Duration: duration,
+		})
+	}
+	return clip, nil
+}
+
+// SpriteSheetEntry describes one animated sprite: its clips by state and how it is spawned
+type SpriteSheetEntry struct {
+	Name  string              `json:"name,omitempty"` // Display name; defaults to the entry's key in the sheet
+	Key   string              `json:"key,omitempty"`  // Key that spawns the sprite; empty for none
+	Clips map[string]ClipSpec `json:"clips"`
+}
+
+// Build creates the runtime clips. An idle clip is required.
+func (e SpriteSheetEntry) Build() (map[SpriteState]*AnimationClip, error) {
+	clips := make(map[SpriteState]*AnimationClip, len(e.Clips))
+	for name, spec := range e.Clips {
+		state := SpriteState(name)
+		if state != SpriteIdle && state != SpriteMoving && state != SpriteHit {
+			return nil, fmt.Errorf("unknown clip state %q (want idle, moving or hit)", name)
+		}
+		clip, err := spec.Build()
+		if err != nil {
+			return nil, fmt.Errorf("clip %q: %w", name, err)
+		}
+		clips[state] = clip
+	}
+	if clips[SpriteIdle] == nil {
+		return nil, fmt.Errorf("an idle clip is required")
+	}
+	return clips, nil
+}
+
+// spriteSheetConfig is the top-level layout of a sprite sheet file
+type spriteSheetConfig struct {
+	Sprites map[string]SpriteSheetEntry `json:"sprites"`
+}
+
+// LoadSpriteSheet parses a JSON sprite sheet of the form
+//
+//	{"sprites": {"flame": {"key": "1", "clips": {
+//	    "idle":   {"mode": "pingpong", "frame_duration": 0.2, "frames": ["▲", "△"]},
+//	    "moving": {"frames": [{"symbol": "➤", "color": "#FF8800", "duration": 0.1}, "➢"]},
+//	    "hit":    {"mode": "once", "frames": ["✸", "✶", "·"]}}}}}
+//
+// Every entry is validated by building its clips once.
+func LoadSpriteSheet(r io.Reader) (map[string]SpriteSheetEntry, error) {
+	var config spriteSheetConfig
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("parsing sprite sheet: %w", err)
+	}
+	for name, entry := range config.Sprites {
+		if name == "" {
+			return nil, fmt.Errorf("sprite with an empty name")
+		}
+		if _, err := entry.Build(); err != nil {
+			return nil, fmt.Errorf("sprite %q: %w", name, err)
+		}
+	}
+	return config.Sprites, nil
+}
+
+// LoadSpriteSheetFile reads a sprite sheet from disk
+func LoadSpriteSheetFile(path string) (map[string]SpriteSheetEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadSpriteSheet(f)
+}
+
+// spriteSheetOrder is where sprite sheet types start in the display order, after the built-ins
+const spriteSheetOrder = 100
+
+// capitalize upper-cases the first letter of a sprite name for display
+func capitalize(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// RegisterSpriteSheet registers every sprite in a sheet as its own entity type,
+// so they get control panel buttons, spawn keys and a status breakdown entry.
+// Nothing is registered if any sprite's name or key is already taken.
+func RegisterSpriteSheet(sheet map[string]SpriteSheetEntry) error {
+	names := make([]string, 0, len(sheet))
+	for name := range sheet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]EntityTypeInfo, 0, len(names))
+	keys := map[string]string{}
+	for i, name := range names {
+		entry := sheet[name]
+		if name == "" {
+			return fmt.Errorf("sprite with an empty name")
+		}
+		clips, err := entry.Build()
+		if err != nil {
+			return fmt.Errorf("sprite %q: %w", name, err)
+		}
+		entityType := EntityType(name)
+		display := entry.Name
+		if display == "" {
+			display = capitalize(name)
+		}
+		info := EntityTypeInfo{
+			Type:     entityType,
+			Name:     display,
+			Icon:     clips[SpriteIdle].Frames[0].Symbol,
+			Key:      entry.Key,
+			Material: PlasticMaterial,
+			Shape:    ShapeBox,
+			New: func(x, y float64, size int, color lipgloss.Color) Entity {
+				return NewAnimatedSprite(x, y, size, color, entityType, clips)
+			},
+			Order: spriteSheetOrder + i,
+		}
+		if err := checkEntityType(info); err != nil {
+			return fmt.Errorf("sprite %q: %w", name, err)
+		}
+		if other, ok := keys[entry.Key]; ok && entry.Key != "" {
+			return fmt.Errorf("sprite %q: key %q already spawns %s", name, entry.Key, other)
+		}
+		keys[entry.Key] = name
+		infos = append(infos, info)
+	}
+	for _, info := range infos {
+		if err := RegisterEntityType(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// playFrames advances a player one frame duration at a time and records the frames shown
+func playFrames(p *ClipPlayer, steps int, duration float64) []int {
+	var frames []int
+	for i := 0; i < steps; i++ {
+		p.Advance(duration)
+		frames = append(frames, p.FrameIndex())
+	}
+	return frames
+}
+
+func TestClipPlayerModes(t *testing.T) {
+	symbols := []string{"a", "b", "c"}
+	tests := []struct {
+		mode     AnimationMode
+		expected []int
+	}{
+		{AnimationLoop, []int{1, 2, 0, 1, 2, 0}},
+		{AnimationPingPong, []int{1, 2, 1, 0, 1, 2}},
+		{AnimationOnce, []int{1, 2, 2, 2, 2, 2}},
+	}
+	for _, tt := range tests {
+		clip := NewLoopClip(symbols, 0.1)
+		clip.Mode = tt.mode
+		player := NewClipPlayer(map[SpriteState]*AnimationClip{SpriteIdle: clip})
+
+		got := playFrames(player, len(tt.expected), 0.1)
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected frames %v, got %v", tt.mode, tt.expected, got)
+		}
+		if tt.mode == AnimationOnce && !player.Done() {
+			t.Error("Expected a one-shot clip to finish")
+		}
+	}
+}
+
+func TestClipPlayerFrameDurations(t *testing.T) {
+	clip := &AnimationClip{Mode: AnimationLoop, Frames: []AnimationFrame{
+		{Symbol: "a", Duration: 0.5},
+		{Symbol: "b", Duration: 0.1},
+	}}
+	player := NewClipPlayer(map[SpriteState]*AnimationClip{SpriteIdle: clip})
+
+	player.Advance(0.4)
+	if player.Frame().Symbol != "a" {
+		t.Error("Expected the long frame to still be showing")
+	}
+	player.Advance(0.15)
+	if player.Frame().Symbol != "b" {
+		t.Error("Expected the clip to move on once the frame's duration passed")
+	}
+	player.Advance(0.1)
+	if player.Frame().Symbol != "a" {
+		t.Error("Expected the short frame to last only its own duration")
+	}
+}
+
+func TestSpriteClipStates(t *testing.T) {
+	clips := map[SpriteState]*AnimationClip{
+		SpriteIdle:   NewLoopClip([]string{"i"}, 0.1),
+		SpriteMoving: NewLoopClip([]string{"m"}, 0.1),
+		SpriteHit:    {Mode: AnimationOnce, Frames: []AnimationFrame{{Symbol: "x", Color: "#FF0000", Duration: 0.1}, {Symbol: "y", Duration: 0.1}}},
+	}
+	sprite := NewAnimatedSprite(10, 10, 1, lipgloss.Color("32"), SpriteType, clips)
+
+	sprite.Update(0.05)
+	if sprite.Symbol != "i" {
+		t.Errorf("Expected the idle clip at rest, got %s", sprite.Symbol)
+	}
+
+	sprite.SetVelocity(5, 0)
+	sprite.Update(0.05)
+	if sprite.Symbol != "m" {
+		t.Errorf("Expected the moving clip while moving, got %s", sprite.Symbol)
+	}
+
+	// A hit plays through, with its frame color, before returning to moving
+	sprite.Hit()
+	if sprite.Symbol != "x" || sprite.Color != "#FF0000" {
+		t.Errorf("Expected the hit clip's first frame, got %s in %s", sprite.Symbol, sprite.Color)
+	}
+	sprite.Update(0.1)
+	if sprite.Symbol != "y" || sprite.Color != "32" {
+		t.Errorf("Expected the hit clip to continue in the sprite's color, got %s in %s", sprite.Symbol, sprite.Color)
+	}
+	sprite.Update(0.01)
+	if sprite.Symbol != "m" {
+		t.Errorf("Expected the sprite to resume moving after the hit, got %s", sprite.Symbol)
+	}
+}
+
+func TestCollisionPlaysHitClip(t *testing.T) {
+	clips := map[SpriteState]*AnimationClip{
+		SpriteIdle: NewLoopClip([]string{"i"}, 0.1),
+		SpriteHit:  NewLoopClip([]string{"x"}, 0.1),
+	}
+	pe := NewPhysicsEngine(80, 40)
+	sprite := NewAnimatedSprite(10, 10, 1, lipgloss.Color("32"), SpriteType, clips)
+	sphere := NewSphere(10.5, 10, 1, lipgloss.Color("31"))
+	sprite.SetVelocity(5, 0)
+	sphere.SetVelocity(-5, 0)
+
+	pe.HandleEntityCollisions([]Entity{sprite, sphere})
+
+	if sprite.Symbol != "x" {
+		t.Errorf("Expected the collision to start the hit clip, got %s", sprite.Symbol)
+	}
+}
+
+func TestSetAnimationUsesTimedFrames(t *testing.T) {
+	sprite := NewSprite(10, 10, 1, lipgloss.Color("32"), "★")
+	sprite.SetAnimation([]string{"1", "2"})
+
+	sprite.Update(defaultFrameDuration / 2)
+	if sprite.Symbol != "1" {
+		t.Error("Expected the frame to hold until its duration passes")
+	}
+	sprite.Update(defaultFrameDuration)
+	if sprite.Symbol != "2" || sprite.CurrentFrame != 1 {
+		t.Errorf("Expected the second frame after one frame duration, got %s", sprite.Symbol)
+	}
+	if sprite.Render().Glyph != "2" {
+		t.Error("Expected the sprite to render its current frame")
+	}
+}
+
+func TestLoadSpriteSheet(t *testing.T) {
+	config := `{"sprites": {"flame": {"key": "1", "clips": {
+		"idle":   {"mode": "pingpong", "frame_duration": 0.2, "frames": ["▲", "△"]},
+		"moving": {"frames": [{"symbol": "➤", "color": "#FF8800", "duration": 0.1}, "➢"]},
+		"hit":    {"mode": "once", "frames": ["✸", "·"]}}}}}`
+
+	sheet, err := LoadSpriteSheet(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clips, err := sheet["flame"].Build()
+	if err != nil {
+		t.Fatalf("Unexpected build error: %v", err)
+	}
+	if clips[SpriteIdle].Mode != AnimationPingPong || clips[SpriteIdle].Frames[1].Duration != 0.2 {
+		t.Error("Expected the clip's frame duration to apply to its frames")
+	}
+	if frame := clips[SpriteMoving].Frames[0]; frame.Color != "#FF8800" || frame.Duration != 0.1 {
+		t.Errorf("Expected frame objects to keep their color and duration, got %+v", frame)
+	}
+	if clips[SpriteMoving].Mode != AnimationLoop {
+		t.Error("Expected clips to loop by default")
+	}
+
+	// The sheet registers spawnable types
+	if err := RegisterSpriteSheet(sheet); err != nil {
+		t.Fatalf("Unexpected register error: %v", err)
+	}
+	t.Cleanup(func() { delete(entityTypeRegistry, "flame") })
+	info, ok := entityTypeForKey("1")
+	if !ok || info.Name != "Flame" || info.Icon != "▲" {
+		t.Fatalf("Expected the flame sprite on key 1, got %+v", info)
+	}
+	entity, _ := NewEntityOfType("flame", 5, 5, 1, lipgloss.Color("32"))
+	if entity.Render().Glyph != "▲" {
+		t.Error("Expected the new type to render its idle clip")
+	}
+}
+
+func TestLoadSpriteSheetErrors(t *testing.T) {
+	configs := []string{
+		`{"sprites": {"bad": {"clips": {"moving": {"frames": ["a"]}}}}}`,                 // No idle clip
+		`{"sprites": {"bad": {"clips": {"idle": {"frames": []}}}}}`,                      // No frames
+		`{"sprites": {"bad": {"clips": {"idle": {"mode": "bounce", "frames": ["a"]}}}}}`, // Unknown mode
+		`{"sprites": {"bad": {"clips": {"idle": {"frames": ["a"]}, "jump": {"frames": ["b"]}}}}}`,
+		`{"sprites": {"bad": {"clips": {"idle": {"frames": [{"symbol": "a", "colour": "1"}]}}}}}`,
+		`{"sprites": {"": {"clips": {"idle": {"frames": ["a"]}}}}}`, // No name
+		`not json`,
+	}
+	for _, config := range configs {
+		if _, err := LoadSpriteSheet(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for %s", config)
+		}
+	}
+}
+
+func TestRegisterSpriteSheetRejectsClashes(t *testing.T) {
+	load := func(config string) map[string]SpriteSheetEntry {
+		sheet, err := LoadSpriteSheet(strings.NewReader(`{"sprites": {` + config + `}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return sheet
+	}
+	const idle = `"clips": {"idle": {"frames": ["a"]}}`
+	configs := []string{
+		`"sphere": {` + idle + `}`,            // Replaces a built-in type
+		`"flare": {"key": "q", ` + idle + `}`, // Shadows quit
+		`"flare": {"key": "0", ` + idle + `}`, // Shadows the camera reset
+		`"flare": {"key": "a", ` + idle + `}`, // Spawns spheres already
+		`"ember": {"key": "7", ` + idle + `}, "flare": {"key": "7", ` + idle + `}`,
+	}
+	for _, config := range configs {
+		if err := RegisterSpriteSheet(load(config)); err == nil {
+			t.Errorf("Expected %s to be rejected", config)
+		}
+	}
+	if _, ok := LookupEntityType("ember"); ok {
+		t.Error("Expected a rejected sheet to register nothing")
+	}
+	if info, _ := LookupEntityType(SphereType); info.Key != "a" || info.Name != "Sphere" {
+		t.Error("Expected the built-in sphere to be left alone")
+	}
+
+	if err := RegisterSpriteSheet(load(`"éclair": {"key": "7", ` + idle + `}`)); err != nil {
+		t.Fatalf("Unexpected register error: %v", err)
+	}
+	t.Cleanup(func() { delete(entityTypeRegistry, "éclair") })
+	if info, _ := LookupEntityType("éclair"); info.Name != "Éclair" {
+		t.Errorf("Expected the first letter capitalized, got %q", info.Name)
+	}
+}
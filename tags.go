@@ -0,0 +1,146 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tagged is implemented by entities that carry tags and metadata
+type tagged interface {
+	HasTag(tag string) bool
+	AddTag(tags ...string)
+	RemoveTag(tag string)
+	GetTags() []string
+	SetMeta(key, value string)
+	GetMeta(key string) (string, bool)
+}
+
+// freezable is implemented by entities that can be held still
+type freezable interface {
+	IsFrozen() bool
+	SetFrozen(frozen bool)
+}
+
+// HasTag reports whether the entity carries a tag
+func (e *BaseEntity) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
+// AddTag adds tags the entity does not already carry
+func (e *BaseEntity) AddTag(tags ...string) {
+	for _, tag := range tags {
+		if tag != "" && !e.HasTag(tag) {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+}
+
+// RemoveTag removes a tag from the entity
+func (e *BaseEntity) RemoveTag(tag string) {
+	e.Tags = slices.DeleteFunc(e.Tags, func(t string) bool { return t == tag })
+}
+
+// GetTags returns a copy of the entity's tags
+func (e *BaseEntity) GetTags() []string {
+	return slices.Clone(e.Tags)
+}
+
+// SetMeta stores a metadata value; an empty value deletes the key
+func (e *BaseEntity) SetMeta(key, value string) {
+	if value == "" {
+		delete(e.Metadata, key)
+		return
+	}
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+	e.Metadata[key] = value
+}
+
+// GetMeta returns a metadata value
+func (e *BaseEntity) GetMeta(key string) (string, bool) {
+	value, ok := e.Metadata[key]
+	return value, ok
+}
+
+// IsFrozen reports whether the entity is held still
+func (e *BaseEntity) IsFrozen() bool {
+	return e.Frozen
+}
+
+// SetFrozen holds the entity still or releases it. Freezing stops it immediately.
+func (e *BaseEntity) SetFrozen(frozen bool) {
+	e.Frozen = frozen
+	if frozen {
+		e.VX, e.VY = 0, 0
+	}
+}
+
+// hasTag reports whether an entity carries a tag
+func hasTag(entity Entity, tag string) bool {
+	t, ok := entity.(tagged)
+	return ok && t.HasTag(tag)
+}
+
+// isFrozen reports whether an entity is held still
+func isFrozen(entity Entity) bool {
+	f, ok := entity.(freezable)
+	return ok && f.IsFrozen()
+}
+
+// GetByTag returns the entities carrying a tag, in manager order (thread-safe)
+func (em *EntityManager) GetByTag(tag string) []Entity {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	var result []Entity
+	for _, entity := range em.entities {
+		if hasTag(entity, tag) {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// RemoveByTag removes every entity carrying a tag and returns how many were removed (thread-safe)
+func (em *EntityManager) RemoveByTag(tag string) int {
+	return em.RemoveWhere(func(entity Entity) bool { return hasTag(entity, tag) })
+}
+
+// RecolorByTag recolors every entity carrying a tag and returns how many changed (thread-safe)
+func (em *EntityManager) RecolorByTag(tag string, color lipgloss.Color) int {
+	count := 0
+	for _, entity := range em.GetByTag(tag) {
+		if recolorable, ok := entity.(interface{ SetColor(lipgloss.Color) }); ok {
+			recolorable.SetColor(color)
+			count++
+		}
+	}
+	return count
+}
+
+// FreezeByTag freezes or releases every entity carrying a tag and returns how many changed (thread-safe)
+func (em *EntityManager) FreezeByTag(tag string, frozen bool) int {
+	count := 0
+	for _, entity := range em.GetByTag(tag) {
+		if f, ok := entity.(freezable); ok {
+			f.SetFrozen(frozen)
+			count++
+		}
+	}
+	return count
+}
+
+// ApplyImpulseByTag applies an impulse to every unfrozen entity carrying a tag
+// and returns how many were pushed (thread-safe). Heavier entities change speed less.
+func (em *EntityManager) ApplyImpulseByTag(tag string, ix, iy float64) int {
+	count := 0
+	for _, entity := range em.GetByTag(tag) {
+		if isFrozen(entity) {
+			continue
+		}
+		entity.ApplyForce(ix, iy)
+		count++
+	}
+	return count
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestEntityTagsAndMetadata(t *testing.T) {
+	sphere := NewSphere(5, 5, 1, lipgloss.Color("32"))
+	sphere.AddTag("red-team", "ball", "red-team", "")
+
+	if tags := sphere.GetTags(); len(tags) != 2 || tags[0] != "red-team" || tags[1] != "ball" {
+		t.Errorf("Expected distinct tags in insertion order, got %v", tags)
+	}
+	sphere.RemoveTag("ball")
+	if sphere.HasTag("ball") || !sphere.HasTag("red-team") {
+		t.Error("Expected RemoveTag to drop only the given tag")
+	}
+
+	sphere.SetMeta("owner", "player1")
+	if value, ok := sphere.GetMeta("owner"); !ok || value != "player1" {
+		t.Errorf("Expected stored metadata, got %q", value)
+	}
+	sphere.SetMeta("owner", "")
+	if _, ok := sphere.GetMeta("owner"); ok {
+		t.Error("Expected an empty value to delete the key")
+	}
+}
+
+func TestTagScopedOperations(t *testing.T) {
+	manager := NewEntityManager()
+	red1 := NewSphere(5, 5, 1, lipgloss.Color("32"))
+	red2 := NewSprite(10, 5, 1, lipgloss.Color("32"), "★")
+	blue := NewSphere(15, 5, 1, lipgloss.Color("32"))
+	red1.AddTag("red")
+	red2.AddTag("red")
+	blue.AddTag("blue")
+	manager.AddEntity(red1)
+	manager.AddEntity(blue)
+	manager.AddEntity(red2)
+
+	found := manager.GetByTag("red")
+	if len(found) != 2 || found[0] != Entity(red1) || found[1] != Entity(red2) {
+		t.Fatalf("Expected both red entities in manager order, got %v", found)
+	}
+
+	if n := manager.RecolorByTag("red", lipgloss.Color("196")); n != 2 || red2.GetColor() != "196" || blue.GetColor() != "32" {
+		t.Error("Expected only red entities to be recolored")
+	}
+
+	if n := manager.ApplyImpulseByTag("blue", 0, -5); n != 1 {
+		t.Errorf("Expected one entity pushed, got %d", n)
+	}
+	if _, vy := blue.GetVelocity(); vy >= 0 {
+		t.Error("Expected the impulse to push the blue entity up")
+	}
+
+	if n := manager.RemoveByTag("red"); n != 2 || manager.Count() != 1 {
+		t.Errorf("Expected both red entities removed, got %d removed and %d left", n, manager.Count())
+	}
+}
+
+func TestFrozenEntitiesHoldStill(t *testing.T) {
+	manager := NewEntityManager()
+	pe := NewPhysicsEngine(80, 40)
+	sphere := NewSphere(20, 10, 1, lipgloss.Color("32"))
+	sphere.AddTag("held")
+	sphere.SetVelocity(5, 5)
+	manager.AddEntity(sphere)
+
+	manager.FreezeByTag("held", true)
+	for i := 0; i < 10; i++ {
+		pe.ApplyPhysics(manager.GetEntities())
+	}
+	if x, y := sphere.GetPosition(); x != 20 || y != 10 {
+		t.Errorf("Expected a frozen entity to stay put, got (%.2f, %.2f)", x, y)
+	}
+	if manager.ApplyImpulseByTag("held", 10, 0) != 0 {
+		t.Error("Expected impulses to skip frozen entities")
+	}
+
+	manager.FreezeByTag("held", false)
+	pe.ApplyPhysics(manager.GetEntities())
+	if _, y := sphere.GetPosition(); y == 10 {
+		t.Error("Expected the released entity to fall again")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+// TrailMode selects how motion trails are drawn
+type TrailMode int
+
+const (
+	TrailsOff    TrailMode = iota
+	TrailsDots             // ·∙• growing toward the entity
+	TrailsShades           // ░▒▓ darkening toward the entity
+)
+
+// trailModeNames are shown in the status line
+var trailModeNames = []string{"off", "dots", "shades"}
+
+// trailLengths are the trail lengths cycled with the Y key, in cells
+var trailLengths = []int{6, 12, 24}
+
+// trailGlyphs are ordered oldest to newest for each mode
+var trailGlyphs = map[TrailMode][]string{
+	TrailsDots:   {"·", "∙", "•"},
+	TrailsShades: {"░", "▒", "▓"},
+}
+
+// String returns the mode's name
+func (mode TrailMode) String() string {
+	return trailModeNames[mode]
+}
+
+// trailGlyph returns the glyph for the i-th of n trail points, oldest first
+func trailGlyph(mode TrailMode, i, n int) string {
+	glyphs := trailGlyphs[mode]
+	return glyphs[i*len(glyphs)/n]
+}
+
+// drawTrails draws each entity's recent display positions onto the grid in the entity's
+// color. The older half of a trail is drawn faint so it fades out behind the entity.
+func drawTrails(grid [][]Cell, cam Camera, entities []Entity, mode TrailMode) {
+	if mode == TrailsOff {
+		return
+	}
+	for _, entity := range entities {
+		state := entity.GetAnimationState()
+		if state == nil {
+			continue
+		}
+		n := len(state.Trail)
+		for i, point := range state.Trail {
+			cell := Cell{Glyph: trailGlyph(mode, i, n), Color: entity.GetColor(), Faint: i < n/2}
+			cam.plot(grid, point[0], point[1], cell)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTrailRecordsVisitedCells(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = func() time.Time {
+		clock.Advance(time.Second / 60) // Every update is one frame later
+		return clock.Now()
+	}
+	ae.TrailLength = 3
+	state := ae.NewEntityAnimationState(0.5, 0.5)
+
+	for x := 0.5; x < 6; x++ {
+		state.DisplayX = x
+		state.SetTarget(x+0.3, 0.5) // Still chasing its target, so it is moving
+		ae.UpdateAnimation(state)
+	}
+	ae.UpdateAnimation(state) // Moving within the same cell adds nothing
+
+	if len(state.Trail) != 3 {
+		t.Fatalf("Expected the trail capped at 3 points, got %d", len(state.Trail))
+	}
+	if int(state.Trail[0][0]) != 3 || int(state.Trail[2][0]) != 5 {
+		t.Errorf("Expected the most recent cells oldest first, got %v", state.Trail)
+	}
+
+	// Once the spring settles the trail drains one point per frame
+	for i := 0; i < 200; i++ {
+		ae.UpdateAnimation(state)
+	}
+	if len(state.Trail) != 0 {
+		t.Errorf("Expected the trail to fade away at rest, got %v", state.Trail)
+	}
+
+	ae.TrailLength = 0
+	state.DisplayX = 10.5
+	ae.UpdateAnimation(state)
+	if state.Trail != nil {
+		t.Error("Expected no trail to be recorded when trails are off")
+	}
+}
+
+func TestTrailSurvivesContactsButNotTeleports(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ae := NewAnimationEngine()
+	ae.Clock = clock.Now
+	ae.TrailLength = 12
+	pe := NewPhysicsEngine(80, 20)
+
+	// A ball thrown sideways bounces on the floor and then rolls along it
+	ball := NewSphere(5.5, 10.5, 1, lipgloss.Color("32"))
+	ball.AnimationState = ae.NewEntityAnimationState(ball.X, ball.Y)
+	ball.SetVelocity(30, 20)
+	longest, touched := 0, false
+	for i := 0; i < 60; i++ {
+		pe.ApplyPhysics([]Entity{ball})
+		clock.Advance(time.Second / 60)
+		ball.UpdateAnimation(ae)
+		if _, y := ball.GetPosition(); y >= pe.MaxY-1 {
+			touched = true
+		}
+		if touched {
+			longest = max(longest, len(ball.AnimationState.Trail))
+		}
+	}
+	if !touched || longest < ae.TrailLength {
+		t.Errorf("Expected the trail to keep growing through floor contacts, longest was %d", longest)
+	}
+
+	teleport(ball, 40.5, 5.5)
+	if len(ball.AnimationState.Trail) != 0 {
+		t.Error("Expected a teleport to clear the trail")
+	}
+	clock.Advance(time.Second / 60)
+	ball.UpdateAnimation(ae)
+	if x, _ := ball.GetDisplayPosition(); x != 40.5 || len(ball.AnimationState.Trail) > 1 {
+		t.Errorf("Expected no trail drawn across the teleport, got %v", ball.AnimationState.Trail)
+	}
+}
+
+func TestTrailGlyphs(t *testing.T) {
+	var dots []string
+	for i := 0; i < 6; i++ {
+		dots = append(dots, trailGlyph(TrailsDots, i, 6))
+	}
+	if strings.Join(dots, "") != "··∙∙••" {
+		t.Errorf("Expected dots growing toward the entity, got %s", strings.Join(dots, ""))
+	}
+	if trailGlyph(TrailsShades, 0, 1) != "░" {
+		t.Error("Expected a single-point trail to use the first glyph")
+	}
+}
+
+func TestTrailsDrawnBeneathEntities(t *testing.T) {
+	model := initialModel()
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updatedModel.(Model)
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updatedModel.(Model)
+	if model.trailMode != TrailsDots || model.animationEngine.TrailLength != trailLengths[0] {
+		t.Fatal("Expected y to turn on dot trails")
+	}
+
+	sphere := NewSphere(10.5, 10.5, 1, lipgloss.Color("32"))
+	sphere.AnimationState.Trail = [][2]float64{{6.5, 10.5}, {7.5, 10.5}, {8.5, 10.5}, {9.5, 10.5}, {10.5, 10.5}}
+	model.entityManager.AddEntity(sphere)
+
+	buffer := NewCellBuffer()
+	model.renderer = buffer
+	view := model.renderSimulation()
+	if !strings.Contains(view, "··∙∙●") {
+		t.Error("Expected the trail behind the sphere with the sphere drawn over its newest point")
+	}
+	if cell := buffer.Cell(6, 10); cell != (Cell{Glyph: "·", Color: sphere.GetColor(), Faint: true}) {
+		t.Errorf("Expected the oldest trail point drawn faint in the sphere's color, got %+v", cell)
+	}
+	if !strings.Contains(view, "Trails: dots 6") {
+		t.Error("Expected the trail mode in the status line")
+	}
+
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	model = updatedModel.(Model)
+	if model.animationEngine.TrailLength != trailLengths[1] {
+		t.Error("Expected Y to cycle the trail length")
+	}
+}
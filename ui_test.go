@@ -353,9 +353,12 @@ func TestAnimationProgressValidation(t *testing.T) {
 
 	initialSnapshot := CaptureUISnapshot(&model)
 
-	// Simulate time passing (multiple tick messages)
+	// Simulate time passing (multiple tick messages), with the animation clock in step
+	tickTime := time.Now()
+	model.animationEngine.Clock = func() time.Time { return tickTime }
 	for i := 0; i < 10; i++ {
-		updatedModel, _ = model.Update(tickMsg(time.Now().Add(time.Duration(i) * time.Millisecond * 100)))
+		tickTime = tickTime.Add(time.Millisecond * 100)
+		updatedModel, _ = model.Update(tickMsg(tickTime))
 		model = updatedModel.(Model)
 	}
 